@@ -1,13 +1,41 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"os"
 
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/app"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
 )
 
+// Использование:
+//
+//	app [-c config.yaml]                    — запуск сервиса
+//	app [-c config.yaml] backfill -from ... — загрузка истории цен
+//	app [-c config.yaml] partitions         — обслуживание секций prices
+//	app [-c config.yaml] migrate up|down|status|force — миграции схемы
 func main() {
-	if err := app.Run(); err != nil {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	switch cmd := flag.Arg(0); cmd {
+	case "", "serve":
+		err = app.Run(cfg)
+	case "backfill":
+		err = app.Backfill(cfg, flag.Args()[1:])
+	case "migrate":
+		err = app.Migrate(cfg, flag.Args()[1:])
+	case "partitions":
+		err = app.Partitions(cfg, flag.Args()[1:])
+	default:
+		err = fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
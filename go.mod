@@ -9,6 +9,8 @@ require (
 	github.com/ilyakaznacheev/cleanenv v1.5.0
 	github.com/jackc/pgx/v5 v5.7.5
 	github.com/labstack/echo/v4 v4.13.4
+	github.com/shopspring/decimal v1.4.0
+	golang.org/x/time v0.11.0
 	gopkg.in/telebot.v4 v4.0.0-beta.5
 )
 
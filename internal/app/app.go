@@ -17,26 +17,29 @@ import (
 	repopg "github.com/NastyaGoryachaya/crypto-rate-service/internal/repository/postgres"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/schedulers/scheduler_dispatcher"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/schedulers/scheduler_fetcher"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/schedulers/scheduler_partitions"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/schedulers/scheduler_retention"
+	alertsvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/alerts"
+	coinsvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/coins"
+	partitionsvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/partitions"
 	ratesvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/rates"
+	retentionsvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/retention"
 	subsvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/subscription"
 	botpkg "github.com/NastyaGoryachaya/crypto-rate-service/internal/transport/bot"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/transport/web"
+	"github.com/NastyaGoryachaya/crypto-rate-service/migrations"
 	"github.com/NastyaGoryachaya/crypto-rate-service/pkg/logger"
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
 	"gopkg.in/telebot.v4"
 )
 
-func Run() error {
+// Run — запуск сервиса: HTTP API, бот и планировщики
+func Run(cfg *config.Config) error {
 	// context + signals
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	// config
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return err
-	}
-
 	// logger
 	appLog := logger.New(&cfg.Logger)
 	appLog.Info("starting crypto-rate-service")
@@ -48,36 +51,69 @@ func Run() error {
 		os.Exit(1)
 	}
 
+	// схема должна быть не старше миграций, встроенных в сборку
+	migrator, err := db.NewMigrator(pool, migrations.FS, appLog)
+	if err != nil {
+		return err
+	}
+	if err := migrator.Check(ctx); err != nil {
+		appLog.Error("database schema check failed", slog.String("error", err.Error()))
+		return err
+	}
+
 	// repo
 	coinRepo := repopg.NewCoinRepo(pool)
 	subsRepo := repopg.NewSubscriptionRepo(pool)
+	alertRepo := repopg.NewAlertRepo(pool)
 
 	// client for API CoinGecko
-	provider := api_client.NewClient(config.CoinGeckoConfig{
-		BaseURL:   cfg.CoinGecko.BaseURL,
-		Coins:     cfg.CoinGecko.Coins,
-		Currency:  cfg.CoinGecko.Currency,
-		Timeout:   cfg.CoinGecko.Timeout,
-		UserAgent: cfg.CoinGecko.UserAgent,
-	})
+	coingecko := api_client.NewClient(config.CoinGeckoConfig{
+		BaseURL:    cfg.CoinGecko.BaseURL,
+		Currencies: cfg.CoinGecko.Currencies,
+		Timeout:    cfg.CoinGecko.Timeout,
+		UserAgent:  cfg.CoinGecko.UserAgent,
+		Retry:      cfg.CoinGecko.Retry,
+	}, appLog)
+
+	// rate providers with failover
+	provider, lookup, err := buildProvider(cfg, coingecko, coinRepo, appLog)
+	if err != nil {
+		return err
+	}
 
 	// services
-	ratesSvc := ratesvc.NewService(coinRepo, provider, appLog)
+	ratesSvc := ratesvc.NewService(coinRepo, provider, cfg.CoinGecko.Currencies, appLog)
+	coinsSvc := coinsvc.NewService(coinRepo, lookup, appLog)
 
 	// subscription service (бот)
-	tbot, err := telebot.NewBot(telebot.Settings{
-		Token:  cfg.Telegram.Token,
-		Poller: &telebot.LongPoller{Timeout: 10 * time.Second},
-	})
+	settings, err := botSettings(cfg.Telegram)
 	if err != nil {
 		return err
 	}
-	subsSvc := subsvc.New(tbot, subsRepo, provider, appLog)
+	tbot, err := telebot.NewBot(settings)
+	if err != nil {
+		return err
+	}
+	notifier := botpkg.NewNotifier(tbot)
+	subsSvc := subsvc.New(notifier, subsRepo, ratesSvc, coinRepo, subsvc.DispatchOptions{
+		StaleAfter: cfg.Telegram.StaleAfter,
+		SkipStale:  strings.EqualFold(cfg.Telegram.StalePolicy, "skip"),
+	}, appLog)
+	alertsSvc := alertsvc.NewService(alertRepo, ratesSvc, coinRepo, notifier, alertsvc.Options{
+		Hysteresis: decimal.NewFromFloat(cfg.Telegram.AlertHysteresisPct).Div(decimal.NewFromInt(100)),
+		MaxPerChat: cfg.Telegram.MaxAlertsPerChat,
+	}, appLog)
 
 	// http
 	httpServer := echo.New()
 	rh := web.NewRatesHandler(appLog, ratesSvc, cfg.Server.ReadTimeout)
 	rh.RegisterRoutes(httpServer)
+	if strings.TrimSpace(cfg.Server.AdminToken) != "" {
+		ah := web.NewAdminHandler(appLog, coinsSvc, cfg.Server.AdminToken, cfg.CoinGecko.Timeout)
+		ah.RegisterRoutes(httpServer)
+	} else {
+		appLog.Warn("admin api and /debug/vars disabled: ADMIN_TOKEN is empty")
+	}
 
 	serv := &http.Server{
 		Addr:         cfg.Server.Addr,
@@ -93,24 +129,47 @@ func Run() error {
 		updater = scheduler_fetcher.NewScheduler(ratesSvc, cfg.SchedulerFetcher.Interval, appLog)
 	}
 
+	var retention *scheduler_retention.Scheduler
+	if cfg.SchedulerRetention.Enabled {
+		policy := retentionsvc.Policy{
+			KeepRaw:    cfg.SchedulerRetention.KeepRaw,
+			KeepHourly: cfg.SchedulerRetention.KeepHourly,
+			KeepDaily:  cfg.SchedulerRetention.KeepDaily,
+		}
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		retentionSvc := retentionsvc.NewService(coinRepo, policy, appLog)
+		retention = scheduler_retention.NewScheduler(retentionSvc, cfg.SchedulerRetention.Interval, appLog)
+	}
+
+	var partitions *scheduler_partitions.Scheduler
+	if cfg.SchedulerPartitions.Enabled {
+		policy, err := partitionPolicy(cfg.SchedulerPartitions, cfg.SchedulerRetention)
+		if err != nil {
+			return err
+		}
+		partitionSvc := partitionsvc.NewService(coinRepo, policy, appLog)
+		partitions = scheduler_partitions.NewScheduler(partitionSvc, cfg.SchedulerPartitions.Interval, appLog)
+	}
+
 	// telegram bot
 	var bot *botpkg.Bot
 	if cfg.Telegram.Enabled {
-		token := strings.TrimSpace(cfg.Telegram.Token)
-		if token == "" {
-			return errors.New("telegram enabled but TELEGRAM_BOT_TOKEN is empty")
-		}
 		botSched := scheduler_dispatcher.NewScheduler(subsSvc, cfg.SchedulerDispatcher.Interval, appLog)
 		bot, err = botpkg.New(
 			tbot,
 			ratesSvc,
-			subsSvc, // implements SubscriptionCommander
+			subsSvc,   // implements SubscriptionCommander
+			alertsSvc, // implements AlertCommander
 			appLog,
 			botSched,
 		)
 		if err != nil {
 			return err
 		}
+		// алерты проверяются после каждого цикла загрузки цен
+		ratesSvc.SetPriceObserver(alertsSvc)
 	}
 	// starting goroutines
 	if updater != nil {
@@ -118,6 +177,16 @@ func Run() error {
 		go updater.Start(ctx)
 	}
 
+	if retention != nil {
+		appLog.Info("starting retention")
+		go retention.Start(ctx)
+	}
+
+	if partitions != nil {
+		appLog.Info("starting partition maintenance")
+		go partitions.Start(ctx)
+	}
+
 	if bot != nil {
 		appLog.Info("starting subscription bot")
 		go bot.Start(ctx)
@@ -147,3 +216,16 @@ func Run() error {
 	appLog.Info("crypto-rate-service stopped")
 	return nil
 }
+
+// botSettings — настройки telebot; без telegram.enabled бот не ходит в сеть и токен не нужен
+func botSettings(tc config.TelegramConfig) (telebot.Settings, error) {
+	token := strings.TrimSpace(tc.Token)
+	if tc.Enabled && token == "" {
+		return telebot.Settings{}, errors.New("telegram enabled but TELEGRAM_BOT_TOKEN is empty")
+	}
+	return telebot.Settings{
+		Token:   token,
+		Poller:  &telebot.LongPoller{Timeout: 10 * time.Second},
+		Offline: !tc.Enabled,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+	"gopkg.in/telebot.v4"
+)
+
+func TestBotSettings(t *testing.T) {
+	if _, err := botSettings(config.TelegramConfig{Enabled: true, Token: "  "}); err == nil {
+		t.Fatal("expected error for enabled bot without token")
+	}
+
+	// выключенный бот без токена — офлайн, NewBot не ходит в Telegram
+	settings, err := botSettings(config.TelegramConfig{Enabled: false})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !settings.Offline {
+		t.Fatal("expected offline bot when telegram is disabled")
+	}
+	if _, err := telebot.NewBot(settings); err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+
+	settings, err = botSettings(config.TelegramConfig{Enabled: true, Token: " 123:abc "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Offline || settings.Token != "123:abc" {
+		t.Fatalf("unexpected settings: offline=%v token=%q", settings.Offline, settings.Token)
+	}
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/api_client"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/db"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/providers"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	repopg "github.com/NastyaGoryachaya/crypto-rate-service/internal/repository/postgres"
+	backfillsvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/backfill"
+	"github.com/NastyaGoryachaya/crypto-rate-service/pkg/logger"
+)
+
+// Backfill — команда `backfill`: загрузка истории цен из CoinGecko /market_chart/range.
+//
+//	app -c config.yaml backfill -from 2025-01-01 [-to 2025-06-01] [-coins BTC,ETH] [-currencies usd,eur]
+//
+// Повторный запуск с тем же -from продолжает прерванную загрузку; без -to — догружает до текущего момента.
+func Backfill(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	var (
+		fromArg   = fs.String("from", "", "начало периода: 2006-01-02 или RFC3339 (обязательно)")
+		toArg     = fs.String("to", "", "конец периода: 2006-01-02 или RFC3339 (по умолчанию — сейчас)")
+		coinsArg  = fs.String("coins", "", "символы монет через запятую (по умолчанию — все включённые)")
+		currArg   = fs.String("currencies", strings.Join(cfg.CoinGecko.Currencies, ","), "валюты котировки через запятую")
+		chunk     = fs.Duration("chunk", backfillsvc.DefaultChunk, "период одного запроса к CoinGecko")
+		perMinute = fs.Int("rate", coingeckoProvider(cfg).RateLimitPerMinute, "запросов к CoinGecko в минуту (0 — без ограничения)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, err := parseTime(*fromArg)
+	if err != nil || from.IsZero() {
+		return fmt.Errorf("backfill: -from is required (2006-01-02 or RFC3339)")
+	}
+	to, err := parseTime(*toArg)
+	if err != nil {
+		return fmt.Errorf("backfill: invalid -to: %w", err)
+	}
+	opts := backfillsvc.Options{
+		From:       from,
+		To:         to,
+		Symbols:    splitList(*coinsArg),
+		Currencies: splitList(*currArg),
+		Chunk:      *chunk,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	appLog := logger.New(&cfg.Logger)
+
+	pool, err := db.NewPool(&cfg.Postgres)
+	if err != nil {
+		appLog.Error("db connect failed", slog.String("error", err.Error()))
+		return err
+	}
+	defer pool.Close()
+
+	// отдельный процесс — свой бюджет запросов, по умолчанию как у провайдера coingecko;
+	// лимит — providers.RateLimited, как у провайдера курсов в serve, но запрос ждёт токена, а не отклоняется
+	client := api_client.NewClient(cfg.CoinGecko, appLog)
+	var history interfaces.HistoryProvider = client
+	if *perMinute > 0 {
+		history = providers.NewRateLimited(appLog, api_client.SourceName, client,
+			*perMinute, coingeckoProvider(cfg).RateLimitBurst, backfillMaxWait)
+	}
+
+	svc := backfillsvc.NewService(repopg.NewCoinRepo(pool), history, appLog)
+	saved, err := svc.Run(ctx, opts)
+	if err != nil {
+		appLog.Error("backfill failed", slog.Int("saved", saved), slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// backfillMaxWait — сколько запрос backfill ждёт токена: при последовательных запросах
+// ожидание не дольше интервала между токенами, т.е. минуты даже при лимите 1 запрос в минуту
+const backfillMaxWait = time.Minute
+
+// coingeckoProvider — настройки провайдера CoinGecko из секции providers (нулевые, если его там нет)
+func coingeckoProvider(cfg *config.Config) config.ProviderConfig {
+	for _, pc := range cfg.Providers {
+		if strings.EqualFold(pc.Name, api_client.SourceName) {
+			return pc
+		}
+	}
+	return config.ProviderConfig{}
+}
+
+// parseTime — дата (2006-01-02, UTC) или RFC3339; пустая строка — нулевое время
+func parseTime(s string) (time.Time, error) {
+	if s = strings.TrimSpace(s); s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// splitList — "a, b,,c" → [a b c]
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/db"
+	"github.com/NastyaGoryachaya/crypto-rate-service/migrations"
+	"github.com/NastyaGoryachaya/crypto-rate-service/pkg/logger"
+)
+
+// Migrate — команда `migrate`: встроенные миграции схемы.
+//
+//	app -c config.yaml migrate up [-n N]     — применить N (по умолчанию все) миграций
+//	app -c config.yaml migrate down [-n N]   — откатить N (по умолчанию одну) миграций
+//	app -c config.yaml migrate status        — текущая версия и неприменённые миграции
+//	app -c config.yaml migrate force VERSION — записать версию без выполнения (снять dirty)
+func Migrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate: expected up, down, status or force")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("migrate "+sub, flag.ContinueOnError)
+	n := fs.Int("n", 0, "число миграций (up: 0 — все; down: по умолчанию 1)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	appLog := logger.New(&cfg.Logger)
+
+	pool, err := db.NewPool(&cfg.Postgres)
+	if err != nil {
+		appLog.Error("db connect failed", slog.String("error", err.Error()))
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := db.NewMigrator(pool, migrations.FS, appLog)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "up":
+		applied, err := migrator.Up(ctx, *n)
+		appLog.Info("migrations applied", slog.Int("count", len(applied)))
+		return err
+	case "down":
+		steps := *n
+		if steps <= 0 {
+			steps = 1
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		appLog.Info("migrations reverted", slog.Int("count", len(reverted)))
+		return err
+	case "status":
+		st, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		dirty := ""
+		if st.Dirty {
+			dirty = " (dirty)"
+		}
+		fmt.Printf("version: %d%s, latest: %d\n", st.Version, dirty, st.Latest)
+		for _, mig := range st.Pending {
+			fmt.Printf("pending: %04d_%s\n", mig.Version, mig.Name)
+		}
+		return nil
+	case "force":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("migrate force: expected VERSION")
+		}
+		version, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("migrate force: invalid version %q", fs.Arg(0))
+		}
+		return migrator.Force(ctx, version)
+	default:
+		return fmt.Errorf("migrate: unknown subcommand %q", sub)
+	}
+}
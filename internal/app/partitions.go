@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os/signal"
+	"syscall"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/db"
+	repopg "github.com/NastyaGoryachaya/crypto-rate-service/internal/repository/postgres"
+	partitionsvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/partitions"
+	"github.com/NastyaGoryachaya/crypto-rate-service/pkg/logger"
+)
+
+// Partitions — команда `partitions`: однократное обслуживание месячных секций prices.
+//
+//	app -c config.yaml partitions [-ahead 2] [-keep 8760h] [-expire detach|drop]
+//
+// По умолчанию параметры берутся из секции scheduler_partitions.
+func Partitions(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("partitions", flag.ContinueOnError)
+	pc := cfg.SchedulerPartitions
+	fs.IntVar(&pc.Ahead, "ahead", pc.Ahead, "сколько месяцев вперёд создавать секции")
+	fs.DurationVar(&pc.Keep, "keep", pc.Keep, "срок хранения секции после конца месяца (0 — не истекают)")
+	fs.StringVar(&pc.Expire, "expire", pc.Expire, "что делать с истёкшими секциями: detach|drop")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	policy, err := partitionPolicy(pc, cfg.SchedulerRetention)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	appLog := logger.New(&cfg.Logger)
+
+	pool, err := db.NewPool(&cfg.Postgres)
+	if err != nil {
+		appLog.Error("db connect failed", slog.String("error", err.Error()))
+		return err
+	}
+	defer pool.Close()
+
+	svc := partitionsvc.NewService(repopg.NewCoinRepo(pool), policy, appLog)
+	report, err := svc.MaintainPartitions(ctx)
+	if err != nil {
+		appLog.Error("partition maintenance failed", slog.String("error", err.Error()))
+		return err
+	}
+	appLog.Info("partitions maintained",
+		slog.Any("created", report.Created),
+		slog.Any("detached", report.Detached),
+		slog.Any("dropped", report.Dropped),
+	)
+	return nil
+}
+
+// partitionPolicy — политика секций из конфига. Секции не должны истекать раньше,
+// чем retention свернёт их сырые точки в агрегаты.
+func partitionPolicy(pc config.PartitionsConfig, rc config.RetentionConfig) (partitionsvc.Policy, error) {
+	policy := partitionsvc.Policy{Ahead: pc.Ahead, Keep: pc.Keep}
+	switch pc.Expire {
+	case "", "detach":
+		policy.Detach = true
+	case "drop":
+	default:
+		return policy, fmt.Errorf("partitions: unknown expire mode %q (detach|drop)", pc.Expire)
+	}
+	if err := policy.Validate(); err != nil {
+		return policy, err
+	}
+	if rc.Enabled && policy.Keep > 0 && policy.Keep < rc.KeepRaw {
+		return policy, fmt.Errorf("partitions: keep (%s) must not be less than retention keep_raw (%s)",
+			policy.Keep, rc.KeepRaw)
+	}
+	return policy, nil
+}
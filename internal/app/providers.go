@@ -0,0 +1,86 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/api_client"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/binance_client"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/providers"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	ratesvc "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/rates"
+)
+
+// buildProvider — собирает композитный источник курсов из секций providers и aggregation конфига.
+// Если секция providers пуста — используется только CoinGecko.
+// Лимит каждого провайдера проверяется на бюджет цикла загрузки: один запрос на валюту котировки.
+// В режимах консенсуса ref — последние сохранённые цены для отбраковки выбросов при двух провайдерах.
+// lookup — каталог CoinGecko для админ API в счёт того же лимита, что и загрузка курсов.
+func buildProvider(cfg *config.Config, coingecko *api_client.Client, ref interfaces.LatestPrices, log *slog.Logger) (provider interfaces.CryptoProvider, lookup interfaces.CoinLookup, err error) {
+	available := map[string]interfaces.CryptoProvider{
+		api_client.SourceName:     coingecko,
+		binance_client.SourceName: binance_client.NewClient(cfg.Binance),
+	}
+
+	list := cfg.Providers
+	if len(list) == 0 {
+		list = []config.ProviderConfig{{Name: api_client.SourceName, Timeout: cfg.CoinGecko.Timeout}}
+	}
+	list = append([]config.ProviderConfig(nil), list...)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Priority < list[j].Priority })
+
+	lookup = coingecko
+	var entries []providers.Entry
+	for _, pc := range list {
+		name := strings.ToLower(strings.TrimSpace(pc.Name))
+		p, ok := available[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown provider %q", pc.Name)
+		}
+		if pc.RateLimitPerMinute > 0 {
+			limited := providers.NewRateLimited(log, name, p, pc.RateLimitPerMinute, pc.RateLimitBurst, pc.RateLimitMaxWait)
+			if name == api_client.SourceName {
+				// лимит аккаунта CoinGecko общий — и для выключенного провайдера курсов
+				lookup = limited
+			}
+			p = limited
+		}
+		if !pc.IsEnabled() {
+			log.Info("provider disabled", slog.String("provider", name))
+			continue
+		}
+		if pc.RateLimitPerMinute > 0 && cfg.SchedulerFetcher.Enabled {
+			calls := len(ratesvc.NormalizeCurrencies(cfg.CoinGecko.Currencies))
+			err := providers.CheckBudget(name, pc.RateLimitPerMinute, pc.RateLimitBurst, calls, cfg.SchedulerFetcher.Interval)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		entries = append(entries, providers.Entry{Name: name, Provider: p, Timeout: pc.Timeout})
+		log.Info("provider enabled",
+			slog.String("provider", name),
+			slog.Int("priority", pc.Priority),
+			slog.Int("rate_limit_per_minute", pc.RateLimitPerMinute))
+	}
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("no enabled rate providers")
+	}
+
+	switch mode := strings.ToLower(strings.TrimSpace(cfg.Aggregation.Mode)); mode {
+	case "", "failover":
+		return providers.NewFailover(log, entries...), lookup, nil
+	case string(providers.MethodMedian), string(providers.MethodTrimmedMean):
+		return providers.NewConsensus(log, providers.ConsensusOptions{
+			Method:          providers.Method(mode),
+			MaxDeviationPct: cfg.Aggregation.MaxDeviationPct,
+			TrimPct:         cfg.Aggregation.TrimPct,
+			MinQuotes:       cfg.Aggregation.MinQuotes,
+			Reference:       ref,
+		}, entries...), lookup, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown aggregation mode %q", cfg.Aggregation.Mode)
+	}
+}
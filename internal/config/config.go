@@ -11,13 +11,18 @@ import (
 // Загрузка конфигурации из config.yaml через cleanenv
 
 type Config struct {
-	Server              ServerConfig    `yaml:"server"`
-	SchedulerDispatcher SchedulerConfig `yaml:"scheduler_dispatcher"`
-	SchedulerFetcher    SchedulerConfig `yaml:"scheduler_fetcher"`
-	Postgres            PostgresConfig  `yaml:"postgres"`
-	CoinGecko           CoinGeckoConfig `yaml:"coingecko"`
-	Telegram            TelegramConfig  `yaml:"telegram"`
-	Logger              LoggerConfig    `yaml:"logger"`
+	Server              ServerConfig      `yaml:"server"`
+	SchedulerDispatcher SchedulerConfig   `yaml:"scheduler_dispatcher"`
+	SchedulerFetcher    SchedulerConfig   `yaml:"scheduler_fetcher"`
+	SchedulerRetention  RetentionConfig   `yaml:"scheduler_retention"`
+	SchedulerPartitions PartitionsConfig  `yaml:"scheduler_partitions"`
+	Postgres            PostgresConfig    `yaml:"postgres"`
+	CoinGecko           CoinGeckoConfig   `yaml:"coingecko"`
+	Binance             BinanceConfig     `yaml:"binance"`
+	Providers           []ProviderConfig  `yaml:"providers"`
+	Aggregation         AggregationConfig `yaml:"aggregation"`
+	Telegram            TelegramConfig    `yaml:"telegram"`
+	Logger              LoggerConfig      `yaml:"logger"`
 }
 
 type ServerConfig struct {
@@ -26,6 +31,7 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration `yaml:"write_timeout" env-default:"10s"`
 	IdleTimeout     time.Duration `yaml:"idle_timeout" env-default:"60s"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+	AdminToken      string        `yaml:"admin_token" env:"ADMIN_TOKEN"` // пустой — админ API выключен
 }
 
 type SchedulerConfig struct {
@@ -33,6 +39,28 @@ type SchedulerConfig struct {
 	Interval time.Duration `yaml:"interval" env-default:"5m"`
 }
 
+// RetentionConfig — хранение старых цен: сырые точки старше keep_raw сворачиваются в часовые агрегаты,
+// часовые старше keep_hourly — в дневные, дневные старше keep_daily удаляются.
+// 0 — данные этого разрешения хранятся бессрочно.
+type RetentionConfig struct {
+	Enabled    bool          `yaml:"enabled" env-default:"false"`
+	Interval   time.Duration `yaml:"interval" env-default:"1h"`
+	KeepRaw    time.Duration `yaml:"keep_raw" env-default:"720h"`
+	KeepHourly time.Duration `yaml:"keep_hourly" env-default:"8760h"`
+	KeepDaily  time.Duration `yaml:"keep_daily"`
+}
+
+// PartitionsConfig — обслуживание месячных секций таблицы prices: секции текущего и ahead следующих месяцев
+// создаются заранее, секции, закончившиеся раньше чем keep назад, истекают (0 — не истекают).
+// expire: detach — отсоединить секцию (таблица остаётся), drop — удалить вместе с данными.
+type PartitionsConfig struct {
+	Enabled  bool          `yaml:"enabled" env-default:"true"`
+	Interval time.Duration `yaml:"interval" env-default:"24h"`
+	Ahead    int           `yaml:"ahead" env-default:"2"`
+	Keep     time.Duration `yaml:"keep"`
+	Expire   string        `yaml:"expire" env-default:"detach"` // detach|drop
+}
+
 type LoggerConfig struct {
 	Level  string `yaml:"level"  env-default:"info"` // debug|info|warn|error
 	Format string `yaml:"format" env-default:"text"` // text|json
@@ -52,18 +80,80 @@ type PostgresConfig struct {
 	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time" env-default:"30m"`
 }
 
+// CoinGeckoConfig — API CoinGecko.
+// Currencies — валюты котировки (vs_currency), которые загружаются на каждом цикле;
+// первая — валюта по умолчанию для API и бота.
 type CoinGeckoConfig struct {
-	BaseURL   string        `yaml:"base_url"`
-	Coins     []string      `yaml:"coins"`
-	Currency  string        `yaml:"currency"`
-	Timeout   time.Duration `yaml:"timeout" env-default:"8s"`
-	UserAgent string        `yaml:"user_agent" env-default:"crypto-rate-service/1.0"`
+	BaseURL    string        `yaml:"base_url"`
+	Currencies []string      `yaml:"currencies" env-default:"usd"`
+	Timeout    time.Duration `yaml:"timeout" env-default:"8s"`
+	UserAgent  string        `yaml:"user_agent" env-default:"crypto-rate-service/1.0"`
+	Retry      RetryConfig   `yaml:"retry"`
+}
+
+// RetryConfig — повторы HTTP-запросов при 429/5xx и сетевых ошибках.
+type RetryConfig struct {
+	MaxRetries int           `yaml:"max_retries" env-default:"3"` // 0 — без повторов
+	BaseDelay  time.Duration `yaml:"base_delay" env-default:"500ms"`
+	MaxDelay   time.Duration `yaml:"max_delay" env-default:"10s"`
+}
+
+// BinanceConfig — публичный REST API Binance (/api/v3/ticker/price).
+// Пара для монеты: base_assets[SYMBOL] (или SYMBOL) + quote_assets[currency] (BTC, usd → BTCUSDT).
+// Валюты без quote_asset Binance не обслуживает.
+type BinanceConfig struct {
+	BaseURL     string            `yaml:"base_url" env-default:"https://api.binance.com"`
+	QuoteAssets map[string]string `yaml:"quote_assets" env-default:"usd:USDT"`
+	BaseAssets  map[string]string `yaml:"base_assets"`
+	Timeout     time.Duration     `yaml:"timeout" env-default:"5s"`
+	UserAgent   string            `yaml:"user_agent" env-default:"crypto-rate-service/1.0"`
 }
 
+// ProviderConfig — участие провайдера курсов в композитном источнике.
+// Провайдеры опрашиваются по возрастанию priority; при ошибке — переход к следующему.
+// Лимит запросов (rate_limit_per_minute) общий для всего процесса; 0 — без лимита.
+//
+// cleanenv не применяет env-default к элементам списка, поэтому значения по умолчанию
+// здесь задаются в коде: enabled не указан — провайдер включён, rate_limit_burst 0 — 1.
+type ProviderConfig struct {
+	Name     string        `yaml:"name"` // coingecko|binance
+	Enabled  *bool         `yaml:"enabled"`
+	Priority int           `yaml:"priority"`
+	Timeout  time.Duration `yaml:"timeout"` // 0 — без отдельного таймаута
+
+	RateLimitPerMinute int           `yaml:"rate_limit_per_minute"`
+	RateLimitBurst     int           `yaml:"rate_limit_burst"`    // 0 — 1
+	RateLimitMaxWait   time.Duration `yaml:"rate_limit_max_wait"` // 0 — сразу отклонять, иначе ждать в очереди
+}
+
+// IsEnabled — провайдер включён: enabled: true или поле не указано
+func (pc ProviderConfig) IsEnabled() bool {
+	return pc.Enabled == nil || *pc.Enabled
+}
+
+// AggregationConfig — как объединять ответы провайдеров.
+// failover — первый успешный по priority; median/trimmed_mean — консенсус всех провайдеров.
+type AggregationConfig struct {
+	Mode            string  `yaml:"mode" env-default:"failover"` // failover|median|trimmed_mean
+	MaxDeviationPct float64 `yaml:"max_deviation_pct" env-default:"2"`
+	TrimPct         float64 `yaml:"trim_pct" env-default:"20"`
+	MinQuotes       int     `yaml:"min_quotes" env-default:"1"`
+}
+
+// TelegramConfig — бот и рассылки. Token обязателен только для serve с enabled: true
+// (проверяется при запуске) — команды migrate/partitions/backfill работают без него.
 type TelegramConfig struct {
 	Enabled             bool   `yaml:"enabled" env-default:"false"`
-	Token               string `yaml:"token" env:"TELEGRAM_BOT_TOKEN" env-required:"true"`
+	Token               string `yaml:"token" env:"TELEGRAM_BOT_TOKEN"`
 	DefaultAutoInterval int    `yaml:"default_auto_interval" env-default:"10"` // minutes
+
+	// Авторассылка берёт сохранённые цены; старше stale_after — устаревшие
+	StaleAfter  time.Duration `yaml:"stale_after" env-default:"15m"`
+	StalePolicy string        `yaml:"stale_policy" env-default:"mark"` // mark|skip
+
+	// Алерты: после срабатывания цена должна вернуться за уровень на alert_hysteresis_pct %, чтобы алерт взвёлся снова
+	AlertHysteresisPct float64 `yaml:"alert_hysteresis_pct" env-default:"0.5"`
+	MaxAlertsPerChat   int     `yaml:"max_alerts_per_chat" env-default:"20"`
 }
 
 func LoadConfig() (*Config, error) {
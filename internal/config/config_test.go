@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+func TestProviders_DefaultsForOmittedFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	const yaml = `
+providers:
+  - name: coingecko
+  - name: binance
+    enabled: false
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg Config
+	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Providers) != 2 {
+		t.Fatalf("unexpected providers: %+v", cfg.Providers)
+	}
+	// enabled не указан — провайдер включён
+	if !cfg.Providers[0].IsEnabled() {
+		t.Fatalf("expected provider without enabled to be enabled")
+	}
+	if cfg.Providers[1].IsEnabled() {
+		t.Fatalf("expected enabled: false to disable provider")
+	}
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AlertKind — тип алерта
+type AlertKind string
+
+const (
+	AlertLevel AlertKind = "level" // пересечение ценой уровня
+	AlertMove  AlertKind = "move"  // изменение цены за окно больше порога в процентах
+)
+
+// AlertDirection — сторона уровня, пересечение которой вызывает уведомление
+type AlertDirection string
+
+const (
+	AlertAbove AlertDirection = "above" // цена поднялась до уровня или выше
+	AlertBelow AlertDirection = "below" // цена опустилась до уровня или ниже
+	AlertAny   AlertDirection = "any"   // для move: рост или падение
+)
+
+// Alert — ценовой алерт чата (таблица alerts)
+type Alert struct {
+	ID        int64
+	ChatID    int64
+	Kind      AlertKind
+	Symbol    string
+	Currency  string
+	Direction AlertDirection
+	Level     decimal.Decimal // level — цена; move — порог изменения в процентах
+	Window    time.Duration   // move: окно изменения
+	Cooldown  time.Duration   // move: пауза после срабатывания
+	// Armed — алерт ждёт пересечения. После срабатывания снимается, пока цена
+	// не отойдёт от уровня обратно на величину гистерезиса.
+	Armed       bool
+	TriggeredAt time.Time // последнее срабатывание; нулевое — не срабатывал
+	CreatedAt   time.Time
+}
+
+// Crossed — цена по ту сторону уровня, о которой нужно уведомить
+func (a Alert) Crossed(price decimal.Decimal) bool {
+	switch a.Direction {
+	case AlertAbove:
+		return price.GreaterThanOrEqual(a.Level)
+	case AlertBelow:
+		return price.LessThanOrEqual(a.Level)
+	}
+	return false
+}
+
+// Evaluate — реакция на новую цену. fire — пора уведомить, armed — новое состояние алерта.
+// hysteresis — доля уровня (0.005 = 0.5%), на которую цена должна вернуться, чтобы алерт снова взвёлся:
+// так колебания около уровня не дают повторных уведомлений.
+func (a Alert) Evaluate(price, hysteresis decimal.Decimal) (fire, armed bool) {
+	if a.Armed {
+		if a.Crossed(price) {
+			return true, false
+		}
+		return false, true
+	}
+	band := a.Level.Mul(hysteresis)
+	switch a.Direction {
+	case AlertAbove:
+		return false, price.LessThan(a.Level.Sub(band))
+	case AlertBelow:
+		return false, price.GreaterThan(a.Level.Add(band))
+	}
+	return false, false
+}
+
+// MoveFired — изменение pct (в процентах) за окно достигло порога в направлении алерта
+func (a Alert) MoveFired(pct decimal.Decimal) bool {
+	switch a.Direction {
+	case AlertAbove:
+		return pct.GreaterThanOrEqual(a.Level)
+	case AlertBelow:
+		return pct.LessThanOrEqual(a.Level.Neg())
+	case AlertAny:
+		return pct.Abs().GreaterThanOrEqual(a.Level)
+	}
+	return false
+}
+
+// CoolingDown — после срабатывания не прошла пауза Cooldown
+func (a Alert) CoolingDown(now time.Time) bool {
+	return !a.TriggeredAt.IsZero() && now.Sub(a.TriggeredAt) < a.Cooldown
+}
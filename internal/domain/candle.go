@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Candle - OHLC-свеча: цены в интервале [Time, Time+interval)
+type Candle struct {
+	Time   time.Time // начало интервала (UTC)
+	Open   decimal.Decimal
+	High   decimal.Decimal
+	Low    decimal.Decimal
+	Close  decimal.Decimal
+	Points int // сколько сохранённых цен попало в свечу
+}
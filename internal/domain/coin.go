@@ -0,0 +1,9 @@
+package domain
+
+// CoinInfo - запись реестра отслеживаемых монет (таблица coins)
+type CoinInfo struct {
+	ProviderID string // bitcoin, ethereum (id у провайдера)
+	Symbol     string // BTC, ETH
+	Name       string // Bitcoin, Ethereum
+	Enabled    bool
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// HistoryCursor — позиция в истории цен: время и источник последней цены страницы.
+// Одно время может быть и у сырой цены, и у свёрнутого агрегата (source hourly/daily),
+// поэтому ключ пагинации — пара (Time, Source). Нулевой курсор — первая страница.
+type HistoryCursor struct {
+	Time   time.Time
+	Source string
+}
+
+func (c HistoryCursor) IsZero() bool {
+	return c.Time.IsZero() && c.Source == ""
+}
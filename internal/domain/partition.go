@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// partitionLayout - имя месячной секции prices в формате time.Format
+const partitionLayout = "prices_2006_01"
+
+// Partition - месячная секция таблицы prices: цены за [From, To)
+type Partition struct {
+	Name string // prices_2025_09
+	From time.Time
+	To   time.Time
+}
+
+// MonthPartition - секция месяца, в который попадает t (границы — начало месяца UTC)
+func MonthPartition(t time.Time) Partition {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Partition{Name: from.Format(partitionLayout), From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// ParsePartition - секция по имени prices_YYYY_MM; false — имя не месячной секции
+func ParsePartition(name string) (Partition, bool) {
+	if !strings.HasPrefix(name, "prices_") {
+		return Partition{}, false
+	}
+	t, err := time.Parse(partitionLayout, name)
+	if err != nil {
+		return Partition{}, false
+	}
+	return MonthPartition(t), true
+}
+
+// PartitionReport - итог обслуживания секций
+type PartitionReport struct {
+	Created  []string
+	Detached []string
+	Dropped  []string
+}
@@ -1,10 +1,44 @@
 package domain
 
-import "time"
+import (
+	"time"
 
-// Coin - представляет криптовалюту
+	"github.com/shopspring/decimal"
+)
+
+// Coin - представляет криптовалюту.
+// Цены — точные десятичные числа (prices.value — NUMERIC): float64 теряет знаки у токенов дешевле цента.
 type Coin struct {
 	Symbol    string // BTC, ETH
-	Price     float64
+	Price     decimal.Decimal
+	Currency  string // валюта котировки: usd, eur, rub, btc
+	Source    string // провайдер, от которого получена цена (coingecko, binance, consensus)
 	UpdatedAt time.Time
+	Market    MarketData
+
+	// Заполняются только при агрегации по нескольким провайдерам
+	Quotes    []Quote // котировки провайдеров, из которых посчитана цена
+	SpreadPct float64 // (max-min)/median*100 по всем котировкам
+}
+
+// Quote - котировка одного провайдера для агрегированной цены
+type Quote struct {
+	Source   string
+	Price    decimal.Decimal
+	Rejected bool // отброшена как выброс (отклонение от медианы больше допустимого)
+}
+
+// MarketData - рыночные показатели монеты; nil — провайдер не вернул значение
+type MarketData struct {
+	Volume24h         *float64
+	MarketCap         *float64
+	High24h           *decimal.Decimal
+	Low24h            *decimal.Decimal
+	Change24hPct      *float64
+	CirculatingSupply *float64
+}
+
+// IsZero - нет ни одного показателя
+func (m MarketData) IsZero() bool {
+	return m == MarketData{}
 }
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StatsOptions - окна статистики по монете; нулевые значения — окна по умолчанию (24ч и 1ч)
+type StatsOptions struct {
+	Window time.Duration // окно минимума/максимума
+	Change time.Duration // окно основного изменения цены
+}
+
+// Change - изменение цены за окно; Pct == nil — нет цены на начало окна
+type Change struct {
+	Window time.Duration
+	Pct    *float64
+}
+
+// RateStats - текущая цена монеты и статистика по ней
+type RateStats struct {
+	Latest   Coin
+	Window   time.Duration // окно Min/Max
+	Min, Max decimal.Decimal
+	Change   Change   // изменение за StatsOptions.Change
+	Changes  []Change // изменения за стандартные окна (1ч/24ч/7д/30д)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// ScheduleKind — тип расписания авторассылки
+type ScheduleKind string
+
+const (
+	ScheduleInterval ScheduleKind = "interval" // каждые Interval от последней отправки
+	ScheduleDaily    ScheduleKind = "daily"    // каждый день в Spec (15:04) по времени чата
+	ScheduleCron     ScheduleKind = "cron"     // cron-выражение Spec из 5 полей по времени чата
+)
+
+// Schedule — расписание авторассылки
+type Schedule struct {
+	Kind     ScheduleKind
+	Interval time.Duration // interval: период, кратный минуте
+	Spec     string        // daily: "09:00"; cron: "0 18 * * 1-5"
+}
+
+// QuietHours — тихие часы чата по его часовому поясу: [Start, End) от полуночи, Start > End — через полночь.
+// Нулевое значение — тихие часы выключены.
+type QuietHours struct {
+	Start, End time.Duration
+	Summary    bool // после окончания отправить одну сводку вместо пропущенных рассылок
+}
+
+// Enabled — заданы ли тихие часы
+func (q QuietHours) Enabled() bool {
+	return q.Start != q.End
+}
+
+// Subscription — авторассылка курсов в чат (таблица subscriptions)
+type Subscription struct {
+	ChatID      int64
+	Schedule    Schedule
+	Coins       []string // символы монет; пустой — все отслеживаемые
+	Timezone    string   // часовой пояс чата (IANA); пустой — UTC
+	Quiet       QuietHours
+	NextRun     time.Time
+	MissedSince time.Time // первая рассылка, пропущенная в тихие часы; нулевое — пропусков нет
+}
@@ -3,7 +3,15 @@ package errors
 import "errors"
 
 var (
-	ErrCoinNotFound  = errors.New("coin not found")
-	ErrPriceNotFound = errors.New("price not found")
-	ErrInternal      = errors.New("internal error")
+	ErrCoinNotFound         = errors.New("coin not found")
+	ErrPriceNotFound        = errors.New("price not found")
+	ErrCoinAlreadyExists    = errors.New("coin already exists")
+	ErrUnknownProviderID    = errors.New("unknown provider coin id")
+	ErrInvalidArgument      = errors.New("invalid argument")
+	ErrRateLimited          = errors.New("rate limit exceeded")
+	ErrUnsupportedCurrency  = errors.New("unsupported currency")
+	ErrInternal             = errors.New("internal error")
+	ErrAlertNotFound        = errors.New("alert not found")
+	ErrTooManyAlerts        = errors.New("too many alerts")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
 )
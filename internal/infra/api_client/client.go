@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -11,46 +12,150 @@ import (
 
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/shopspring/decimal"
 )
 
+// SourceName — имя провайдера в поле domain.Coin.Source и в конфиге providers
+const SourceName = "coingecko"
+
 type Client struct {
 	cfg        config.CoinGeckoConfig
 	httpClient *http.Client
+	logger     *slog.Logger
 }
 
 // coingeckoResponse — структура для парсинга ответа API CoinGecko
 type coingeckoResponse struct {
-	ID           string  `json:"id"`
-	Symbol       string  `json:"symbol"`
-	CurrentPrice float64 `json:"current_price"`
+	ID                       string           `json:"id"`
+	Symbol                   string           `json:"symbol"`
+	CurrentPrice             decimal.Decimal  `json:"current_price"` // разбирается из текста JSON без потери знаков
+	TotalVolume              *float64         `json:"total_volume"`
+	MarketCap                *float64         `json:"market_cap"`
+	High24h                  *decimal.Decimal `json:"high_24h"`
+	Low24h                   *decimal.Decimal `json:"low_24h"`
+	PriceChangePercentage24h *float64         `json:"price_change_percentage_24h"`
+	CirculatingSupply        *float64         `json:"circulating_supply"`
+	LastUpdated              time.Time        `json:"last_updated"`
+}
+
+// coingeckoListItem — элемент каталога монет /coins/list
+type coingeckoListItem struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
 }
 
 // NewClient - Создаёт нового клиента для работы с API CoinGecko.
-func NewClient(cfg config.CoinGeckoConfig) *Client {
+// Запросы повторяются при 429/5xx согласно cfg.Retry.
+func NewClient(cfg config.CoinGeckoConfig, logger *slog.Logger) *Client {
 	return &Client{
 		cfg: cfg,
 		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
+			Timeout:   cfg.Timeout,
+			Transport: newRetryTransport(http.DefaultTransport, cfg.Retry, cfg.Timeout, logger),
 		},
+		logger: logger,
+	}
+}
+
+// FetchRates — получает курсы в валюте currency по API CoinGecko для переданных монет реестра.
+// Ответ сопоставляется с реестром по id CoinGecko, а не по символу:
+// у разных монет символы могут совпадать.
+func (c *Client) FetchRates(ctx context.Context, coins []domain.CoinInfo, currency string) ([]domain.Coin, error) {
+	if len(coins) == 0 {
+		return nil, nil
+	}
+	byID := make(map[string]domain.CoinInfo, len(coins))
+	ids := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		byID[coin.ProviderID] = coin
+		ids = append(ids, coin.ProviderID)
+	}
+
+	q := url.Values{}
+	currency = strings.ToLower(currency)
+	q.Set("vs_currency", currency)
+	q.Set("ids", strings.Join(ids, ","))
+
+	var data []coingeckoResponse
+	if err := c.getJSON(ctx, q, &data, "coins", "markets"); err != nil {
+		return nil, err
+	}
+
+	var result []domain.Coin
+	for _, d := range data {
+		coin, ok := byID[d.ID]
+		if !ok {
+			c.logger.Warn("coingecko: unmapped id in response, skipping",
+				slog.String("id", d.ID),
+				slog.String("symbol", d.Symbol))
+			continue
+		}
+		if !strings.EqualFold(d.Symbol, coin.Symbol) {
+			c.logger.Debug("coingecko: symbol differs from registry",
+				slog.String("id", d.ID),
+				slog.String("provider_symbol", d.Symbol),
+				slog.String("registry_symbol", coin.Symbol))
+		}
+		updatedAt := d.LastUpdated.UTC()
+		if d.LastUpdated.IsZero() {
+			updatedAt = time.Now().UTC()
+		}
+		result = append(result, domain.Coin{
+			Symbol:    strings.ToUpper(coin.Symbol),
+			Price:     d.CurrentPrice,
+			Currency:  currency,
+			Source:    SourceName,
+			UpdatedAt: updatedAt,
+			Market: domain.MarketData{
+				Volume24h:         d.TotalVolume,
+				MarketCap:         d.MarketCap,
+				High24h:           d.High24h,
+				Low24h:            d.Low24h,
+				Change24hPct:      d.PriceChangePercentage24h,
+				CirculatingSupply: d.CirculatingSupply,
+			},
+		})
 	}
+	return result, nil
 }
 
-// FetchRates — получает курсы валют по API CoinGecko
-func (c *Client) FetchRates(ctx context.Context) ([]domain.Coin, error) {
+// LookupCoin — ищет монету по id в каталоге CoinGecko (/coins/list).
+// Если id не найден — возвращает errs.ErrUnknownProviderID.
+func (c *Client) LookupCoin(ctx context.Context, providerID string) (domain.CoinInfo, error) {
+	var data []coingeckoListItem
+	if err := c.getJSON(ctx, nil, &data, "coins", "list"); err != nil {
+		return domain.CoinInfo{}, err
+	}
+
+	for _, d := range data {
+		if d.ID == providerID {
+			return domain.CoinInfo{
+				ProviderID: d.ID,
+				Symbol:     strings.ToUpper(d.Symbol),
+				Name:       d.Name,
+				Enabled:    true,
+			}, nil
+		}
+	}
+	return domain.CoinInfo{}, fmt.Errorf("%w: %s", errs.ErrUnknownProviderID, providerID)
+}
+
+// getJSON — выполняет GET-запрос к BaseURL/<path...> и декодирует JSON-ответ в out
+func (c *Client) getJSON(ctx context.Context, q url.Values, out any, path ...string) error {
 	u, err := url.Parse(c.cfg.BaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid base URL: %w", err)
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	u.Path, _ = url.JoinPath(u.Path, path...)
+	if q != nil {
+		u.RawQuery = q.Encode()
 	}
-	u.Path, _ = url.JoinPath(u.Path, "coins", "markets")
-
-	q := u.Query()
-	q.Set("vs_currency", strings.ToLower(c.cfg.Currency))
-	q.Set("ids", strings.Join(c.cfg.Coins, ","))
-	u.RawQuery = q.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
@@ -63,26 +168,16 @@ func (c *Client) FetchRates(ctx context.Context) ([]domain.Coin, error) {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed: %s", resp.Status)
+		return fmt.Errorf("request failed: %s", resp.Status)
 	}
 
-	var data []coingeckoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
 	}
-
-	var result []domain.Coin
-	for _, d := range data {
-		result = append(result, domain.Coin{
-			Symbol:    strings.ToUpper(d.Symbol),
-			Price:     d.CurrentPrice,
-			UpdatedAt: time.Now().UTC(),
-		})
-	}
-	return result, nil
+	return nil
 }
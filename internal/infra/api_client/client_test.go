@@ -0,0 +1,120 @@
+package api_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+)
+
+func TestFetchRates_KeysByProviderID(t *testing.T) {
+	// два актива с одинаковым тикером + лишний id, которого нет в реестре
+	const body = `[
+		{"id":"bitcoin","symbol":"btc","current_price":61234.56,"market_cap":1212345678901,
+		 "total_volume":35123456789.12,"high_24h":62100,"low_24h":null,
+		 "price_change_percentage_24h":1.27,"circulating_supply":19725000,
+		 "last_updated":"2025-09-16T12:34:56.789Z"},
+		{"id":"batcat","symbol":"btc","current_price":0.0012},
+		{"id":"unlisted","symbol":"xyz","current_price":1}
+	]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("ids"); got != "bitcoin,batcat" {
+			t.Errorf("unexpected ids param: %s", got)
+		}
+		if got := r.URL.Query().Get("vs_currency"); got != "eur" {
+			t.Errorf("unexpected vs_currency param: %s", got)
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	got, err := newRetryClient(srv.URL, 0).FetchRates(context.Background(), []domain.CoinInfo{
+		{ProviderID: "bitcoin", Symbol: "BTC"},
+		{ProviderID: "batcat", Symbol: "BTCAT"},
+	}, "eur")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 rates (unmapped id skipped), got %+v", got)
+	}
+	if got[0].Symbol != "BTC" || got[0].Price.String() != "61234.56" || got[0].Currency != "eur" {
+		t.Fatalf("unexpected bitcoin rate: %+v", got[0])
+	}
+	// время — last_updated провайдера, а не момент запроса
+	if want := time.Date(2025, 9, 16, 12, 34, 56, 789e6, time.UTC); !got[0].UpdatedAt.Equal(want) {
+		t.Fatalf("unexpected updated_at: %s", got[0].UpdatedAt)
+	}
+	m := got[0].Market
+	if m.MarketCap == nil || *m.MarketCap != 1212345678901 || m.Volume24h == nil || m.High24h == nil ||
+		m.Low24h != nil || m.Change24hPct == nil || *m.Change24hPct != 1.27 || m.CirculatingSupply == nil {
+		t.Fatalf("unexpected market data: %+v", m)
+	}
+	if got[1].Symbol != "BTCAT" || got[1].Price.String() != "0.0012" {
+		t.Fatalf("unexpected batcat rate: %+v", got[1])
+	}
+}
+
+func TestLookupCoin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/list" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[{"id":"solana","symbol":"sol","name":"Solana"}]`))
+	}))
+	defer srv.Close()
+
+	c := newRetryClient(srv.URL, 0)
+	got, err := c.LookupCoin(context.Background(), "solana")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Symbol != "SOL" || got.Name != "Solana" || !got.Enabled {
+		t.Fatalf("unexpected coin: %+v", got)
+	}
+	if _, err := c.LookupCoin(context.Background(), "nope"); err == nil {
+		t.Fatalf("expected error for unknown id")
+	}
+}
+
+func TestFetchRange(t *testing.T) {
+	const body = `{
+		"prices": [[1726488000000, 0.00001234567], [1726491600000, null], [1726495200000, 0.0000125]],
+		"market_caps": [[1726488000000, 7270000000.5]],
+		"total_volumes": [[1726488000000, 150000000]]
+	}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/shiba-inu/market_chart/range" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("vs_currency") != "usd" || q.Get("from") != "1726488000" || q.Get("to") != "1726495200" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	from := time.Unix(1726488000, 0)
+	got, err := newRetryClient(srv.URL, 0).FetchRange(context.Background(),
+		domain.CoinInfo{ProviderID: "shiba-inu", Symbol: "shib"}, "USD", from, from.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 points (null skipped), got %+v", got)
+	}
+	p := got[0]
+	if p.Symbol != "SHIB" || p.Currency != "usd" || p.Price.String() != "0.00001234567" || !p.UpdatedAt.Equal(from) {
+		t.Fatalf("unexpected point: %+v", p)
+	}
+	if p.Market.MarketCap == nil || *p.Market.MarketCap != 7270000000.5 || p.Market.Volume24h == nil {
+		t.Fatalf("unexpected market data: %+v", p.Market)
+	}
+	if got[1].Market.MarketCap != nil {
+		t.Fatalf("expected no market cap for second point")
+	}
+}
@@ -0,0 +1,85 @@
+package api_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// marketChartResponse — ответ /coins/{id}/market_chart/range: точки [unix_ms, значение]
+type marketChartResponse struct {
+	Prices       [][2]json.Number `json:"prices"`
+	MarketCaps   [][2]json.Number `json:"market_caps"`
+	TotalVolumes [][2]json.Number `json:"total_volumes"`
+}
+
+// FetchRange — исторические цены монеты в валюте currency за период [from, to].
+// Шаг точек выбирает CoinGecko: до суток — ~5 минут, до 90 дней — час, дольше — день.
+func (c *Client) FetchRange(ctx context.Context, coin domain.CoinInfo, currency string, from, to time.Time) ([]domain.Coin, error) {
+	currency = strings.ToLower(currency)
+
+	q := url.Values{}
+	q.Set("vs_currency", currency)
+	q.Set("from", strconv.FormatInt(from.Unix(), 10))
+	q.Set("to", strconv.FormatInt(to.Unix(), 10))
+	q.Set("precision", "full")
+
+	var data marketChartResponse
+	if err := c.getJSON(ctx, q, &data, "coins", coin.ProviderID, "market_chart", "range"); err != nil {
+		return nil, err
+	}
+
+	caps := pointsByTime(data.MarketCaps)
+	volumes := pointsByTime(data.TotalVolumes)
+
+	result := make([]domain.Coin, 0, len(data.Prices))
+	for _, p := range data.Prices {
+		if p[1] == "" { // null — у провайдера нет цены на эту точку
+			continue
+		}
+		ts, err := p[0].Int64()
+		if err != nil {
+			return nil, fmt.Errorf("parsing timestamp %q: %w", p[0], err)
+		}
+		price, err := decimal.NewFromString(p[1].String())
+		if err != nil {
+			return nil, fmt.Errorf("parsing price %q: %w", p[1], err)
+		}
+		result = append(result, domain.Coin{
+			Symbol:    strings.ToUpper(coin.Symbol),
+			Price:     price,
+			Currency:  currency,
+			Source:    SourceName,
+			UpdatedAt: time.UnixMilli(ts).UTC(),
+			Market: domain.MarketData{
+				MarketCap: caps[ts],
+				Volume24h: volumes[ts],
+			},
+		})
+	}
+	return result, nil
+}
+
+// pointsByTime — точки [unix_ms, значение] по времени; нечисловые значения пропускаются
+func pointsByTime(points [][2]json.Number) map[int64]*float64 {
+	out := make(map[int64]*float64, len(points))
+	for _, p := range points {
+		ts, err := p[0].Int64()
+		if err != nil {
+			continue
+		}
+		v, err := p[1].Float64()
+		if err != nil {
+			continue
+		}
+		out[ts] = &v
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package api_client
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+)
+
+// metrics — счётчики HTTP-клиента CoinGecko (доступны через /debug/vars с токеном администратора)
+var metrics = expvar.NewMap("coingecko_client")
+
+// retryTransport — http.RoundTripper с повторами:
+// 429 — ждём Retry-After (или backoff, если заголовка нет),
+// 5xx и сетевые ошибки — экспоненциальный backoff с jitter.
+// Ожидание никогда не выходит за дедлайн контекста запроса и общий таймаут клиента.
+type retryTransport struct {
+	base    http.RoundTripper
+	cfg     config.RetryConfig
+	timeout time.Duration // общий таймаут http.Client (включает все попытки)
+	logger  *slog.Logger
+}
+
+func newRetryTransport(base http.RoundTripper, cfg config.RetryConfig, timeout time.Duration, logger *slog.Logger) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{base: base, cfg: cfg, timeout: timeout, logger: logger}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	deadline, hasDeadline := ctx.Deadline()
+	if t.timeout > 0 {
+		if d := time.Now().Add(t.timeout); !hasDeadline || d.Before(deadline) {
+			deadline, hasDeadline = d, true
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		metrics.Add("requests", 1)
+		resp, err := t.base.RoundTrip(req)
+
+		wait, retryable := t.retryDelay(resp, err, attempt)
+		if !retryable {
+			return resp, err
+		}
+		if attempt >= t.cfg.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			metrics.Add("retry_exhausted", 1)
+			t.logger.Warn("coingecko: retries exhausted",
+				slog.String("url", req.URL.Path),
+				slog.Int("retries", attempt),
+				slog.String("reason", retryReason(resp, err)))
+			return resp, err
+		}
+		if hasDeadline && time.Now().Add(wait).After(deadline) {
+			metrics.Add("retry_deadline", 1)
+			t.logger.Warn("coingecko: retry would exceed deadline, giving up",
+				slog.String("url", req.URL.Path),
+				slog.Int("retries", attempt),
+				slog.Duration("wait", wait),
+				slog.String("reason", retryReason(resp, err)))
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		metrics.Add("retries", 1)
+		t.logger.Warn("coingecko: retrying request",
+			slog.String("url", req.URL.Path),
+			slog.Int("attempt", attempt+1),
+			slog.Int("max_retries", t.cfg.MaxRetries),
+			slog.Duration("wait", wait),
+			slog.String("reason", retryReason(resp, err)))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// retryDelay — нужно ли повторять запрос и сколько ждать перед повтором
+func (t *retryTransport) retryDelay(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		return t.backoff(attempt), true
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d, true
+		}
+		return t.backoff(attempt), true
+	case resp.StatusCode >= 500:
+		return t.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// backoff — base * 2^attempt (не больше MaxDelay) со случайным jitter в [d/2, d]
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.cfg.BaseDelay
+	for i := 0; i < attempt && (t.cfg.MaxDelay <= 0 || d < t.cfg.MaxDelay); i++ {
+		d *= 2
+	}
+	if t.cfg.MaxDelay > 0 && d > t.cfg.MaxDelay {
+		d = t.cfg.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter — Retry-After в секундах или в виде HTTP-даты
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		d := time.Until(at)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
@@ -0,0 +1,114 @@
+package api_client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+)
+
+const marketsBody = `[{"id":"bitcoin","symbol":"btc","current_price":61234.56}]`
+
+// sequence — сервер, отвечающий статусами из списка по очереди (последний повторяется)
+func sequence(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(statuses[n])
+		if statuses[n] == http.StatusOK {
+			_, _ = w.Write([]byte(marketsBody))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newRetryClient(baseURL string, retries int) *Client {
+	return NewClient(config.CoinGeckoConfig{
+		BaseURL: baseURL,
+		Timeout: 2 * time.Second,
+		Retry: config.RetryConfig{
+			MaxRetries: retries,
+			BaseDelay:  time.Millisecond,
+			MaxDelay:   5 * time.Millisecond,
+		},
+	}, slog.Default())
+}
+
+var bitcoin = []domain.CoinInfo{{ProviderID: "bitcoin", Symbol: "BTC"}}
+
+func TestRetry_429ThenOK(t *testing.T) {
+	srv, calls := sequence(t, []int{http.StatusTooManyRequests, http.StatusOK}, http.Header{"Retry-After": {"0"}})
+
+	got, err := newRetryClient(srv.URL, 3).FetchRates(context.Background(), bitcoin, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || calls.Load() != 2 {
+		t.Fatalf("expected success after one retry, got %+v (calls=%d)", got, calls.Load())
+	}
+}
+
+func TestRetry_5xxExhausted(t *testing.T) {
+	srv, calls := sequence(t, []int{http.StatusBadGateway}, nil)
+
+	_, err := newRetryClient(srv.URL, 2).FetchRates(context.Background(), bitcoin, "usd")
+	if err == nil {
+		t.Fatalf("expected error after retries exhausted")
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 1 call + 2 retries, got %d", calls.Load())
+	}
+}
+
+func TestRetry_NoRetryOn4xx(t *testing.T) {
+	srv, calls := sequence(t, []int{http.StatusNotFound}, nil)
+
+	if _, err := newRetryClient(srv.URL, 3).FetchRates(context.Background(), bitcoin, "usd"); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected no retries on 404, got %d calls", calls.Load())
+	}
+}
+
+func TestRetry_RetryAfterBeyondDeadline(t *testing.T) {
+	srv, calls := sequence(t, []int{http.StatusTooManyRequests, http.StatusOK}, http.Header{"Retry-After": {"30"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	started := time.Now()
+	if _, err := newRetryClient(srv.URL, 3).FetchRates(ctx, bitcoin, "usd"); err == nil {
+		t.Fatalf("expected 429 error")
+	}
+	if calls.Load() != 1 || time.Since(started) > 500*time.Millisecond {
+		t.Fatalf("expected immediate give-up, calls=%d elapsed=%s", calls.Load(), time.Since(started))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter("7"); !ok || d != 7*time.Second {
+		t.Fatalf("unexpected seconds parse: %v %v", d, ok)
+	}
+	at := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d, ok := parseRetryAfter(at); !ok || d <= 0 || d > time.Minute {
+		t.Fatalf("unexpected date parse: %v %v", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Fatalf("expected invalid value to be rejected")
+	}
+}
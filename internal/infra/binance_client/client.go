@@ -0,0 +1,235 @@
+package binance_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/shopspring/decimal"
+)
+
+// SourceName — имя провайдера в поле domain.Coin.Source и в конфиге providers
+const SourceName = "binance"
+
+const (
+	// invalidSymbolCode — код ошибки Binance «Invalid symbol.»: пары нет на бирже
+	invalidSymbolCode = -1121
+	// invalidPairTTL — через сколько перепроверить несуществующую пару (монету могут залистить)
+	invalidPairTTL = 24 * time.Hour
+)
+
+type Client struct {
+	cfg        config.BinanceConfig
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	invalid map[string]time.Time // несуществующая пара → когда перепроверить
+}
+
+// tickerPrice — элемент ответа /api/v3/ticker/price
+type tickerPrice struct {
+	Symbol string `json:"symbol"` // торговая пара, например BTCUSDT
+	Price  string `json:"price"`
+}
+
+// apiError — тело ошибки Binance API
+type apiError struct {
+	Status string `json:"-"`
+	Code   int    `json:"code"`
+	Msg    string `json:"msg"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("request failed: %s: %s (code %d)", e.Status, e.Msg, e.Code)
+}
+
+// NewClient - Создаёт нового клиента для публичного REST API Binance.
+func NewClient(cfg config.BinanceConfig) *Client {
+	return &Client{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		invalid: make(map[string]time.Time),
+	}
+}
+
+// Pair — торговая пара для символа монеты в валюте котировки:
+// <BASE_ASSET><QUOTE_ASSET> (BTC, usd → BTCUSDT). false — для валюты не настроен quote asset
+// или монета сама является quote asset (BTC в btc — пары BTCBTC не существует).
+func (c *Client) Pair(symbol, currency string) (string, bool) {
+	quote := strings.ToUpper(lookupFold(c.cfg.QuoteAssets, currency))
+	if quote == "" {
+		return "", false
+	}
+	base := strings.ToUpper(symbol)
+	if b := lookupFold(c.cfg.BaseAssets, symbol); b != "" {
+		base = strings.ToUpper(b)
+	}
+	if base == quote {
+		return "", false
+	}
+	return base + quote, true
+}
+
+// lookupFold — значение по ключу без учёта регистра (ключи конфига пишутся как угодно)
+func lookupFold(m map[string]string, key string) string {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// FetchRates — получает последние цены сделок по торговым парам монет реестра в валюте currency.
+// Пары, которых нет на Binance (монета без пары к EUR и т.п.), пропускаются: Binance отклоняет
+// весь запрос с -1121, если в нём есть хоть одна такая пара, поэтому после отказа пары
+// запрашиваются по одной, а несуществующие запоминаются на invalidPairTTL.
+func (c *Client) FetchRates(ctx context.Context, coins []domain.CoinInfo, currency string) ([]domain.Coin, error) {
+	if len(coins) == 0 {
+		return nil, nil
+	}
+	currency = strings.ToLower(currency)
+	if _, ok := c.Pair("", currency); !ok {
+		return nil, fmt.Errorf("%w: %s: no quote asset configured", errs.ErrUnsupportedCurrency, currency)
+	}
+
+	bySymbol := make(map[string]string, len(coins)) // пара → символ монеты
+	pairs := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		p, ok := c.Pair(coin.Symbol, currency)
+		if !ok || c.isInvalid(p) {
+			continue
+		}
+		if _, dup := bySymbol[p]; dup {
+			continue
+		}
+		bySymbol[p] = strings.ToUpper(coin.Symbol)
+		pairs = append(pairs, p)
+	}
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	data, err := c.tickers(ctx, pairs)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.Code == invalidSymbolCode {
+		data, err = c.tickersEach(ctx, pairs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	var result []domain.Coin
+	for _, d := range data {
+		symbol, ok := bySymbol[d.Symbol]
+		if !ok {
+			continue
+		}
+		price, err := decimal.NewFromString(d.Price)
+		if err != nil {
+			return nil, fmt.Errorf("parsing price for %s: %w", d.Symbol, err)
+		}
+		result = append(result, domain.Coin{
+			Symbol:    symbol,
+			Price:     price,
+			Currency:  currency,
+			Source:    SourceName,
+			UpdatedAt: now,
+		})
+	}
+	return result, nil
+}
+
+// tickersEach — цены пар по одной; пары, отклонённые как несуществующие, запоминаются и пропускаются
+func (c *Client) tickersEach(ctx context.Context, pairs []string) ([]tickerPrice, error) {
+	var out []tickerPrice
+	for _, p := range pairs {
+		data, err := c.tickers(ctx, []string{p})
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.Code == invalidSymbolCode {
+			c.markInvalid(p)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, data...)
+	}
+	return out, nil
+}
+
+// tickers — запрос /api/v3/ticker/price по списку пар
+func (c *Client) tickers(ctx context.Context, pairs []string) ([]tickerPrice, error) {
+	u, err := url.Parse(c.cfg.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	u.Path, _ = url.JoinPath(u.Path, "api", "v3", "ticker", "price")
+
+	symbolsParam, _ := json.Marshal(pairs)
+	q := u.Query()
+	q.Set("symbols", string(symbolsParam))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.UserAgent != "" {
+		req.Header.Set("User-Agent", c.cfg.UserAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &apiError{Status: resp.Status}
+		if json.NewDecoder(resp.Body).Decode(apiErr) == nil && apiErr.Msg != "" {
+			return nil, apiErr
+		}
+		return nil, fmt.Errorf("request failed: %s", resp.Status)
+	}
+
+	var data []tickerPrice
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return data, nil
+}
+
+// isInvalid — пара недавно отклонена Binance как несуществующая
+func (c *Client) isInvalid(pair string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	until, ok := c.invalid[pair]
+	if ok && time.Now().After(until) {
+		delete(c.invalid, pair)
+		return false
+	}
+	return ok
+}
+
+func (c *Client) markInvalid(pair string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalid[pair] = time.Now().Add(invalidPairTTL)
+}
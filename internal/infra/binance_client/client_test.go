@@ -0,0 +1,180 @@
+package binance_client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/config"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+)
+
+// replay — httptest-сервер, отдающий записанный ответ Binance из testdata
+func replay(t *testing.T, status int, fixture string, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+	body, err := os.ReadFile("testdata/" + fixture)
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if check != nil {
+			check(r)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(baseURL string) *Client {
+	return NewClient(config.BinanceConfig{
+		BaseURL:     baseURL,
+		QuoteAssets: map[string]string{"usd": "usdt", "eur": "EUR"},
+		Timeout:     time.Second,
+	})
+}
+
+func TestPair(t *testing.T) {
+	c := NewClient(config.BinanceConfig{
+		QuoteAssets: map[string]string{"USD": "USDT", "eur": "EUR"},
+		BaseAssets:  map[string]string{"MIOTA": "iota"},
+	})
+	if got, ok := c.Pair("btc", "usd"); !ok || got != "BTCUSDT" {
+		t.Fatalf("unexpected pair: %s", got)
+	}
+	if got, ok := c.Pair("btc", "EUR"); !ok || got != "BTCEUR" {
+		t.Fatalf("unexpected eur pair: %s", got)
+	}
+	if got, ok := c.Pair("MIOTA", "usd"); !ok || got != "IOTAUSDT" {
+		t.Fatalf("unexpected override pair: %s", got)
+	}
+	if _, ok := c.Pair("btc", "rub"); ok {
+		t.Fatalf("expected no pair for currency without quote asset")
+	}
+	c.cfg.QuoteAssets["btc"] = "BTC"
+	if _, ok := c.Pair("BTC", "btc"); ok {
+		t.Fatalf("expected no pair for the quote asset itself")
+	}
+}
+
+func TestFetchRates_Success(t *testing.T) {
+	srv := replay(t, http.StatusOK, "ticker_price.json", func(r *http.Request) {
+		if r.URL.Path != "/api/v3/ticker/price" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("symbols"); got != `["BTCUSDT","ETHUSDT"]` {
+			t.Errorf("unexpected symbols param: %s", got)
+		}
+	})
+
+	got, err := newTestClient(srv.URL).FetchRates(context.Background(), []domain.CoinInfo{
+		{ProviderID: "bitcoin", Symbol: "BTC"},
+		{ProviderID: "ethereum", Symbol: "eth"},
+	}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 rates, got %d", len(got))
+	}
+	if got[0].Symbol != "BTC" || got[0].Price.String() != "115913.37" || got[0].Source != SourceName || got[0].Currency != "usd" {
+		t.Fatalf("unexpected BTC rate: %+v", got[0])
+	}
+	if got[1].Symbol != "ETH" || got[1].Price.String() != "4524.47" {
+		t.Fatalf("unexpected ETH rate: %+v", got[1])
+	}
+	if got[0].UpdatedAt.IsZero() {
+		t.Fatalf("expected UpdatedAt to be set")
+	}
+}
+
+func TestFetchRates_APIError(t *testing.T) {
+	srv := replay(t, http.StatusTooManyRequests, "too_many_requests.json", nil)
+
+	_, err := newTestClient(srv.URL).FetchRates(context.Background(), []domain.CoinInfo{
+		{ProviderID: "bitcoin", Symbol: "BTC"},
+	}, "usd")
+	if err == nil || !strings.Contains(err.Error(), "Too many requests") || !strings.Contains(err.Error(), "-1003") {
+		t.Fatalf("expected Binance error message, got %v", err)
+	}
+}
+
+func TestFetchRates_SkipsUnlistedPairs(t *testing.T) {
+	invalid, _ := os.ReadFile("testdata/invalid_symbol.json")
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		symbols := r.URL.Query().Get("symbols")
+		requests = append(requests, symbols)
+		w.Header().Set("Content-Type", "application/json")
+		// как Binance: одна несуществующая пара — отказ всего запроса
+		if strings.Contains(symbols, "NOPEEUR") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write(invalid)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"symbol":"BTCEUR","price":"98000.50"}]`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+	coins := []domain.CoinInfo{{ProviderID: "bitcoin", Symbol: "BTC"}, {ProviderID: "nope", Symbol: "NOPE"}}
+	got, err := c.FetchRates(context.Background(), coins, "eur")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Symbol != "BTC" || got[0].Price.String() != "98000.5" {
+		t.Fatalf("expected only BTC rate, got %+v", got)
+	}
+	if want := []string{`["BTCEUR","NOPEEUR"]`, `["BTCEUR"]`, `["NOPEEUR"]`}; strings.Join(requests, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+
+	// несуществующая пара запомнена — следующий цикл обходится одним запросом
+	requests = nil
+	if _, err := c.FetchRates(context.Background(), coins, "eur"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 1 || requests[0] != `["BTCEUR"]` {
+		t.Fatalf("expected unlisted pair to be skipped, got %v", requests)
+	}
+}
+
+func TestFetchRates_SkipsQuoteAssetItself(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("symbols"); got != `["ETHBTC"]` {
+			t.Errorf("unexpected symbols param: %s", got)
+		}
+		_, _ = w.Write([]byte(`[{"symbol":"ETHBTC","price":"0.039"}]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(config.BinanceConfig{BaseURL: srv.URL, QuoteAssets: map[string]string{"btc": "BTC"}, Timeout: time.Second})
+	got, err := c.FetchRates(context.Background(), []domain.CoinInfo{{Symbol: "BTC"}, {Symbol: "ETH"}}, "btc")
+	if err != nil || len(got) != 1 || got[0].Symbol != "ETH" {
+		t.Fatalf("expected only ETH rate (no BTCBTC pair), got %+v, %v", got, err)
+	}
+}
+
+func TestFetchRates_UnsupportedCurrency(t *testing.T) {
+	_, err := newTestClient("http://unused").FetchRates(context.Background(), []domain.CoinInfo{
+		{ProviderID: "bitcoin", Symbol: "BTC"},
+	}, "rub")
+	if !errors.Is(err, errs.ErrUnsupportedCurrency) {
+		t.Fatalf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+}
+
+func TestFetchRates_Empty(t *testing.T) {
+	got, err := newTestClient("http://unused").FetchRates(context.Background(), nil, "usd")
+	if err != nil || got != nil {
+		t.Fatalf("expected no request for empty coin list, got %v, %v", got, err)
+	}
+}
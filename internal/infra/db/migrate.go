@@ -0,0 +1,307 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Встроенные миграции. Версия схемы хранится в schema_migrations в формате golang-migrate,
+// поэтому база, размеченная контейнером migrate/migrate, подхватывается без изменений.
+
+// migrateLockKey — ключ advisory lock: параллельные `migrate` не применяют миграции дважды
+const migrateLockKey int64 = 7_431_905_112
+
+var (
+	// ErrSchemaDirty — миграция упала на полпути; схему нужно поправить вручную и выполнить `migrate force`
+	ErrSchemaDirty = errors.New("database schema is dirty")
+	// ErrSchemaBehind — в базе применены не все миграции этой сборки
+	ErrSchemaBehind = errors.New("database schema is behind")
+)
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration — одна версия схемы
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus — состояние схемы относительно миграций сборки
+type MigrationStatus struct {
+	Version int64 // 0 — миграции не применялись
+	Dirty   bool
+	Latest  int64
+	Pending []Migration
+}
+
+// LoadMigrations — миграции из файлов NNNN_name.up.sql / NNNN_name.down.sql по возрастанию версии.
+// down-файл необязателен: такую миграцию нельзя откатить.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := migrationFile.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing or empty up file", mig.Version, mig.Name)
+		}
+		out = append(out, *mig)
+	}
+	slices.SortFunc(out, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return out, nil
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// NewMigrator — мигратор по файлам из fsys (обычно migrations.FS)
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS, logger *slog.Logger) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	return &Migrator{pool: pool, migrations: migrations, logger: logger}, nil
+}
+
+// Latest — последняя версия схемы, известная сборке
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status — текущая версия схемы и неприменённые миграции. Базу не изменяет.
+func (m *Migrator) Status(ctx context.Context) (MigrationStatus, error) {
+	st := MigrationStatus{Latest: m.Latest()}
+	var exists bool
+	if err := m.pool.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return st, err
+	}
+	if exists {
+		var err error
+		if st.Version, st.Dirty, err = readVersion(ctx, m.pool); err != nil {
+			return st, err
+		}
+	}
+	for _, mig := range m.migrations {
+		if mig.Version > st.Version {
+			st.Pending = append(st.Pending, mig)
+		}
+	}
+	return st, nil
+}
+
+// Check — ошибка, если схема отстаёт от сборки или осталась в dirty-состоянии.
+// Схема новее сборки (откат бинарника без отката миграций) — только предупреждение.
+func (m *Migrator) Check(ctx context.Context) error {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if st.Dirty {
+		return fmt.Errorf("%w: migration %d failed half-way; fix the schema manually and run `migrate force %d`",
+			ErrSchemaDirty, st.Version, st.Version)
+	}
+	if len(st.Pending) > 0 {
+		return fmt.Errorf("%w: database is at version %d, this build requires %d; run `migrate up`",
+			ErrSchemaBehind, st.Version, st.Latest)
+	}
+	if st.Version > st.Latest {
+		m.logger.Warn("database schema is newer than this build",
+			slog.Int64("version", st.Version), slog.Int64("latest", st.Latest))
+	}
+	return nil
+}
+
+// Up — применить до limit неприменённых миграций (limit <= 0 — все); возвращает применённые.
+func (m *Migrator) Up(ctx context.Context, limit int) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w: version %d", ErrSchemaDirty, current)
+		}
+		for _, mig := range m.migrations {
+			if mig.Version <= current {
+				continue
+			}
+			if limit > 0 && len(applied) == limit {
+				break
+			}
+			m.logger.Info("applying migration", slog.Int64("version", mig.Version), slog.String("name", mig.Name))
+			// версия помечается dirty до выполнения: упавшая миграция видна в status
+			if err := writeVersion(ctx, conn, mig.Version, true); err != nil {
+				return err
+			}
+			if _, err := conn.Exec(ctx, mig.Up); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			if err := writeVersion(ctx, conn, mig.Version, false); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down — откатить steps последних применённых миграций; возвращает откаченные.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w: version %d", ErrSchemaDirty, current)
+		}
+		for ; steps > 0 && current > 0; steps-- {
+			i := slices.IndexFunc(m.migrations, func(mig Migration) bool { return mig.Version == current })
+			if i < 0 {
+				return fmt.Errorf("database is at version %d, unknown to this build", current)
+			}
+			mig := m.migrations[i]
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+			}
+			var prev int64
+			if i > 0 {
+				prev = m.migrations[i-1].Version
+			}
+
+			m.logger.Info("reverting migration", slog.Int64("version", mig.Version), slog.String("name", mig.Name))
+			if err := writeVersion(ctx, conn, mig.Version, true); err != nil {
+				return err
+			}
+			if _, err := conn.Exec(ctx, mig.Down); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			if err := writeVersion(ctx, conn, prev, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, mig)
+			current = prev
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force — записать версию схемы без выполнения миграций и снять dirty (после ручного исправления).
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(mig Migration) bool { return mig.Version == version }) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return writeVersion(ctx, conn, version, false)
+	})
+}
+
+// withLock — выполнить fn на отдельном соединении под advisory lock, создав schema_migrations при необходимости.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrateLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// контекст мог быть отменён — освобождаем блокировку независимо от него
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrateLockKey); err != nil {
+			m.logger.Warn("release migration lock failed", slog.String("error", err.Error()))
+		}
+	}()
+
+	if _, err := conn.Exec(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// readVersion — версия из schema_migrations; пустая таблица — версия 0
+func readVersion(ctx context.Context, q querier) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := q.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// writeVersion — заменить версию схемы; версия 0 без dirty — пустая таблица, как у golang-migrate
+func writeVersion(ctx context.Context, conn *pgxpool.Conn, version int64, dirty bool) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+			return err
+		}
+		if version == 0 && !dirty {
+			return nil
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, version, dirty)
+		return err
+	})
+}
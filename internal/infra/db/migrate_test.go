@@ -0,0 +1,62 @@
+package db
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_b.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"0002_a.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"0002_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"embed.go":        {Data: []byte("package migrations")},
+	}
+	got, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// по возрастанию версии, а не по имени файла; down необязателен
+	if len(got) != 2 || got[0].Version != 2 || got[0].Name != "a" || got[0].Down != "DROP TABLE a;" ||
+		got[1].Version != 10 || got[1].Down != "" {
+		t.Fatalf("unexpected migrations: %+v", got)
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"missing up": {"0001_a.down.sql": {Data: []byte("DROP TABLE a;")}},
+		"empty up":   {"0001_a.up.sql": {Data: []byte("")}},
+		"conflicting names": {
+			"0001_a.up.sql": {Data: []byte("SELECT 1;")},
+			"0001_b.up.sql": {Data: []byte("SELECT 1;")},
+		},
+		"zero version": {"0000_a.up.sql": {Data: []byte("SELECT 1;")}},
+	}
+	for name, fsys := range cases {
+		if _, err := LoadMigrations(fsys); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatalf("no embedded migrations")
+	}
+	// версии идут подряд, и каждую можно откатить
+	for i, mig := range got {
+		if mig.Version != int64(i+1) {
+			t.Fatalf("migration %s: expected version %d, got %d", mig.Name, i+1, mig.Version)
+		}
+		if mig.Down == "" {
+			t.Errorf("migration %d_%s: missing down file", mig.Version, mig.Name)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// ConsensusSource — значение domain.Coin.Source для агрегированной цены
+const ConsensusSource = "consensus"
+
+// Method — способ свёртки котировок в одну цену
+type Method string
+
+const (
+	MethodMedian      Method = "median"
+	MethodTrimmedMean Method = "trimmed_mean"
+)
+
+// ConsensusOptions — параметры агрегации котировок
+type ConsensusOptions struct {
+	Method          Method
+	MaxDeviationPct float64 // котировки дальше от медианы отбрасываются (0 — не отбрасывать)
+	TrimPct         float64 // для trimmed_mean: % котировок, отбрасываемых с каждого края
+	MinQuotes       int     // минимум принятых котировок, иначе монета пропускается
+
+	// Reference — последние сохранённые цены. При двух котировках медиана равноудалена от обеих,
+	// и выброс определяется по медиане из двух котировок и последней сохранённой цены (nil — не отбраковывать).
+	Reference interfaces.LatestPrices
+}
+
+// Consensus — агрегирующий CryptoProvider: опрашивает всех провайдеров параллельно
+// и для каждой монеты считает консенсус-цену (медиана или усечённое среднее),
+// отбрасывая котировки, отклоняющиеся от медианы больше допустимого.
+type Consensus struct {
+	entries []Entry
+	opts    ConsensusOptions
+	logger  *slog.Logger
+}
+
+func NewConsensus(logger *slog.Logger, opts ConsensusOptions, entries ...Entry) *Consensus {
+	if opts.Method == "" {
+		opts.Method = MethodMedian
+	}
+	if opts.MinQuotes <= 0 {
+		opts.MinQuotes = 1
+	}
+	return &Consensus{entries: entries, opts: opts, logger: logger}
+}
+
+// FetchRates — котировки всех провайдеров, свёрнутые в одну цену на монету.
+// Котировки и разброс между провайдерами возвращаются в Quotes/SpreadPct.
+func (c *Consensus) FetchRates(ctx context.Context, coins []domain.CoinInfo, currency string) ([]domain.Coin, error) {
+	if len(coins) == 0 {
+		return nil, nil
+	}
+
+	type answer struct {
+		rates []domain.Coin
+		err   error
+	}
+	answers := make([]answer, len(c.entries))
+	var wg sync.WaitGroup
+	for i, e := range c.entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rates, err := fetchEntry(ctx, c.logger, e, coins, currency)
+			answers[i] = answer{rates: rates, err: err}
+		}()
+	}
+	wg.Wait()
+
+	var failures []error
+	quotes := make(map[string][]domain.Quote)
+	updated := make(map[string]time.Time)
+	market := make(map[string]domain.MarketData) // от первого провайдера, который их вернул
+	for i, a := range answers {
+		name := c.entries[i].Name
+		if a.err != nil {
+			c.logger.Warn("provider failed, excluded from consensus",
+				slog.String("provider", name),
+				slog.String("err", a.err.Error()))
+			failures = append(failures, fmt.Errorf("%s: %w", name, a.err))
+			continue
+		}
+		for _, r := range a.rates {
+			sym := strings.ToUpper(r.Symbol)
+			quotes[sym] = append(quotes[sym], domain.Quote{Source: name, Price: r.Price})
+			if r.UpdatedAt.After(updated[sym]) {
+				updated[sym] = r.UpdatedAt
+			}
+			if _, ok := market[sym]; !ok && !r.Market.IsZero() {
+				market[sym] = r.Market
+			}
+		}
+	}
+	if len(quotes) == 0 {
+		if len(failures) == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("all providers failed: %w", errors.Join(failures...))
+	}
+
+	refs := c.references(ctx, quotes, currency)
+
+	var result []domain.Coin
+	for _, coin := range coins {
+		sym := strings.ToUpper(coin.Symbol)
+		qs, ok := quotes[sym]
+		if !ok {
+			continue
+		}
+		item, ok := c.aggregate(sym, qs, refs[sym])
+		if !ok {
+			continue
+		}
+		item.Currency = currency
+		item.UpdatedAt = updated[sym]
+		item.Market = market[sym]
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// references — последние сохранённые цены монет, у которых ровно две котировки.
+// Ошибка хранилища не роняет цикл: такие монеты сворачиваются без отбраковки.
+func (c *Consensus) references(ctx context.Context, quotes map[string][]domain.Quote, currency string) map[string]decimal.Decimal {
+	if c.opts.Reference == nil || c.opts.MaxDeviationPct <= 0 {
+		return nil
+	}
+	need := false
+	for _, qs := range quotes {
+		if len(qs) == 2 {
+			need = true
+			break
+		}
+	}
+	if !need {
+		return nil
+	}
+	latest, err := c.opts.Reference.GetAllCoins(ctx, currency)
+	if err != nil {
+		c.logger.Warn("reference prices unavailable, two-quote coins not checked for outliers",
+			slog.String("currency", currency),
+			slog.String("err", err.Error()))
+		return nil
+	}
+	refs := make(map[string]decimal.Decimal, len(latest))
+	for _, l := range latest {
+		refs[strings.ToUpper(l.Symbol)] = l.Price
+	}
+	return refs
+}
+
+// aggregate — консенсус-цена по котировкам одной монеты; ref — последняя сохранённая цена (ноль — нет)
+func (c *Consensus) aggregate(symbol string, qs []domain.Quote, ref decimal.Decimal) (domain.Coin, bool) {
+	prices := make([]decimal.Decimal, 0, len(qs))
+	for _, q := range qs {
+		prices = append(prices, q.Price)
+	}
+	med := median(prices)
+	if !med.IsPositive() {
+		c.logger.Warn("non-positive median, skipping coin", slog.String("symbol", symbol))
+		return domain.Coin{}, false
+	}
+	spread := pctOf(decimal.Max(prices[0], prices[1:]...).Sub(decimal.Min(prices[0], prices[1:]...)), med)
+
+	// С двумя котировками медиана равноудалена от обеих — выброс не определить,
+	// поэтому третьей опорой служит последняя сохранённая цена; без неё отбраковка — с трёх котировок.
+	center, checkable := med, len(qs) >= 3
+	if len(qs) == 2 && ref.IsPositive() {
+		center, checkable = median([]decimal.Decimal{prices[0], prices[1], ref}), true
+	}
+	accepted := make([]decimal.Decimal, 0, len(qs))
+	for i := range qs {
+		dev := pctOf(qs[i].Price.Sub(center).Abs(), center)
+		if checkable && c.opts.MaxDeviationPct > 0 && dev > c.opts.MaxDeviationPct {
+			qs[i].Rejected = true
+			c.logger.Warn("quote rejected as outlier",
+				slog.String("symbol", symbol),
+				slog.String("provider", qs[i].Source),
+				slog.String("price", qs[i].Price.String()),
+				slog.String("median", center.String()),
+				slog.Float64("deviation_pct", dev))
+			continue
+		}
+		accepted = append(accepted, qs[i].Price)
+	}
+	if len(accepted) < c.opts.MinQuotes {
+		c.logger.Warn("not enough quotes for consensus",
+			slog.String("symbol", symbol),
+			slog.Int("accepted", len(accepted)),
+			slog.Int("min_quotes", c.opts.MinQuotes))
+		return domain.Coin{}, false
+	}
+
+	var price decimal.Decimal
+	switch c.opts.Method {
+	case MethodTrimmedMean:
+		price = trimmedMean(accepted, c.opts.TrimPct)
+	default:
+		price = median(accepted)
+	}
+
+	c.logger.Debug("consensus computed",
+		slog.String("symbol", symbol),
+		slog.String("price", price.String()),
+		slog.Int("quotes", len(qs)),
+		slog.Int("accepted", len(accepted)),
+		slog.Float64("spread_pct", spread))
+
+	return domain.Coin{
+		Symbol:    symbol,
+		Price:     price,
+		Source:    ConsensusSource,
+		Quotes:    qs,
+		SpreadPct: spread,
+	}, true
+}
+
+// pctOf — part/base*100; проценты дальше сравниваются с порогами конфига во float
+func pctOf(part, base decimal.Decimal) float64 {
+	return part.Div(base).Mul(decimal.NewFromInt(100)).InexactFloat64()
+}
+
+// sorted — отсортированная копия (вход не изменяется)
+func sorted(values []decimal.Decimal) []decimal.Decimal {
+	s := slices.Clone(values)
+	slices.SortFunc(s, func(a, b decimal.Decimal) int { return a.Cmp(b) })
+	return s
+}
+
+// median — медиана (вход не изменяется)
+func median(values []decimal.Decimal) decimal.Decimal {
+	s := sorted(values)
+	n := len(s)
+	if n == 0 {
+		return decimal.Zero
+	}
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return s[n/2-1].Add(s[n/2]).Div(decimal.NewFromInt(2))
+}
+
+// trimmedMean — среднее после отбрасывания trimPct% значений с каждого края
+func trimmedMean(values []decimal.Decimal, trimPct float64) decimal.Decimal {
+	s := sorted(values)
+	n := len(s)
+	if n == 0 {
+		return decimal.Zero
+	}
+	k := int(float64(n) * trimPct / 100)
+	if 2*k >= n {
+		k = (n - 1) / 2
+	}
+	return decimal.Sum(decimal.Zero, s[k:n-k]...).Div(decimal.NewFromInt(int64(n - 2*k)))
+}
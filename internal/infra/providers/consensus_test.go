@@ -0,0 +1,185 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"testing"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	ratesmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/rates/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+// quoting — провайдер-заглушка, отдающий фиксированную цену BTC
+func quoting(ctrl *gomock.Controller, price float64) *ratesmocks.MockCryptoProvider {
+	p := ratesmocks.NewMockCryptoProvider(ctrl)
+	p.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").
+		Return([]domain.Coin{{Symbol: "BTC", Price: decimal.NewFromFloat(price), UpdatedAt: now}}, nil)
+	return p
+}
+
+func almostEqual(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestConsensus_MedianRejectsOutlier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := NewConsensus(slog.Default(), ConsensusOptions{Method: MethodMedian, MaxDeviationPct: 2},
+		Entry{Name: "a", Provider: quoting(ctrl, 100)},
+		Entry{Name: "b", Provider: quoting(ctrl, 101)},
+		Entry{Name: "bad", Provider: quoting(ctrl, 150)},
+	)
+	got, err := c.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 coin, got %d", len(got))
+	}
+	r := got[0]
+	// медиана по принятым (100, 101) = 100.5
+	if !r.Price.Equal(decimal.RequireFromString("100.5")) || r.Source != ConsensusSource || !r.UpdatedAt.Equal(now) {
+		t.Fatalf("unexpected consensus: %+v", r)
+	}
+	// spread = (150-100)/101*100
+	if !almostEqual(r.SpreadPct, 50.0/101*100) {
+		t.Fatalf("unexpected spread: %v", r.SpreadPct)
+	}
+	if len(r.Quotes) != 3 || r.Quotes[0].Rejected || r.Quotes[1].Rejected || !r.Quotes[2].Rejected {
+		t.Fatalf("unexpected quotes: %+v", r.Quotes)
+	}
+}
+
+func TestConsensus_TwoQuotesNotRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := NewConsensus(slog.Default(), ConsensusOptions{Method: MethodMedian, MaxDeviationPct: 1},
+		Entry{Name: "a", Provider: quoting(ctrl, 100)},
+		Entry{Name: "b", Provider: quoting(ctrl, 110)},
+	)
+	got, err := c.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || !got[0].Price.Equal(decimal.NewFromInt(105)) {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+	for _, q := range got[0].Quotes {
+		if q.Rejected {
+			t.Fatalf("quote must not be rejected with only two providers: %+v", q)
+		}
+	}
+}
+
+func TestConsensus_TwoQuotesRejectedByReference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ref := ratesmocks.NewMockLatestPrices(ctrl)
+	ref.EXPECT().GetAllCoins(gomock.Any(), "usd").
+		Return([]domain.Coin{{Symbol: "BTC", Price: decimal.NewFromInt(101)}}, nil)
+
+	c := NewConsensus(slog.Default(), ConsensusOptions{Method: MethodMedian, MaxDeviationPct: 2, Reference: ref},
+		Entry{Name: "a", Provider: quoting(ctrl, 100)},
+		Entry{Name: "bad", Provider: quoting(ctrl, 150)},
+	)
+	got, err := c.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// медиана (100, 150, 101) = 101: 150 отклоняется на 48%, в цену идёт только 100
+	if len(got) != 1 || !got[0].Price.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+	if q := got[0].Quotes; len(q) != 2 || q[0].Rejected || !q[1].Rejected {
+		t.Fatalf("unexpected quotes: %+v", q)
+	}
+}
+
+func TestConsensus_TwoQuotesStaleReferenceKeepsAgreeingQuotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// цена ушла далеко от сохранённой, но провайдеры согласны — обе котировки принимаются
+	ref := ratesmocks.NewMockLatestPrices(ctrl)
+	ref.EXPECT().GetAllCoins(gomock.Any(), "usd").
+		Return([]domain.Coin{{Symbol: "BTC", Price: decimal.NewFromInt(80)}}, nil)
+
+	c := NewConsensus(slog.Default(), ConsensusOptions{Method: MethodMedian, MaxDeviationPct: 2, Reference: ref},
+		Entry{Name: "a", Provider: quoting(ctrl, 100)},
+		Entry{Name: "b", Provider: quoting(ctrl, 101)},
+	)
+	got, err := c.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || !got[0].Price.Equal(decimal.RequireFromString("100.5")) {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+	for _, q := range got[0].Quotes {
+		if q.Rejected {
+			t.Fatalf("quote must not be rejected: %+v", q)
+		}
+	}
+}
+
+func TestConsensus_TrimmedMean(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := NewConsensus(slog.Default(), ConsensusOptions{Method: MethodTrimmedMean, TrimPct: 20},
+		Entry{Name: "a", Provider: quoting(ctrl, 99)},
+		Entry{Name: "b", Provider: quoting(ctrl, 100)},
+		Entry{Name: "c", Provider: quoting(ctrl, 101)},
+		Entry{Name: "d", Provider: quoting(ctrl, 102)},
+		Entry{Name: "e", Provider: quoting(ctrl, 130)},
+	)
+	got, err := c.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// по одной котировке с каждого края отброшено: (100+101+102)/3
+	if len(got) != 1 || !got[0].Price.Equal(decimal.NewFromInt(101)) {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
+
+func TestConsensus_ProviderFailureTolerated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	down := ratesmocks.NewMockCryptoProvider(ctrl)
+	down.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").Return(nil, errors.New("down"))
+
+	c := NewConsensus(slog.Default(), ConsensusOptions{Method: MethodMedian},
+		Entry{Name: "down", Provider: down},
+		Entry{Name: "a", Provider: quoting(ctrl, 100)},
+	)
+	got, err := c.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || !got[0].Price.Equal(decimal.NewFromInt(100)) || len(got[0].Quotes) != 1 {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
+
+func TestConsensus_MinQuotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := NewConsensus(slog.Default(), ConsensusOptions{Method: MethodMedian, MinQuotes: 2},
+		Entry{Name: "a", Provider: quoting(ctrl, 100)},
+	)
+	got, err := c.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected coin to be skipped, got %+v", got)
+	}
+}
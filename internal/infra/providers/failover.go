@@ -0,0 +1,113 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+)
+
+// Entry — провайдер в составе композитного источника курсов
+type Entry struct {
+	Name     string
+	Provider interfaces.CryptoProvider
+	Timeout  time.Duration // 0 — без собственного таймаута
+}
+
+// Failover — композитный CryptoProvider: опрашивает провайдеров по порядку
+// и переходит к следующему при ошибке, таймауте или нехватке монет в ответе.
+type Failover struct {
+	entries []Entry
+	logger  *slog.Logger
+}
+
+// NewFailover — entries должны быть упорядочены по приоритету
+func NewFailover(logger *slog.Logger, entries ...Entry) *Failover {
+	return &Failover{entries: entries, logger: logger}
+}
+
+// FetchRates — возвращает курсы от первого успешно ответившего провайдера.
+// Монеты, которых не оказалось в ответе, запрашиваются у следующих провайдеров.
+// Source каждой цены — имя провайдера, который её вернул.
+func (f *Failover) FetchRates(ctx context.Context, coins []domain.CoinInfo, currency string) ([]domain.Coin, error) {
+	if len(coins) == 0 {
+		return nil, nil
+	}
+
+	var (
+		result    []domain.Coin
+		failures  []error
+		remaining = coins
+	)
+	for _, e := range f.entries {
+		if len(remaining) == 0 {
+			break
+		}
+
+		rates, err := fetchEntry(ctx, f.logger, e, remaining, currency)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", e.Name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			f.logger.Warn("provider failed, falling back",
+				slog.String("provider", e.Name),
+				slog.String("err", err.Error()))
+			continue
+		}
+
+		got := make(map[string]struct{}, len(rates))
+		for _, r := range rates {
+			if r.Source == "" {
+				r.Source = e.Name
+			}
+			got[strings.ToUpper(r.Symbol)] = struct{}{}
+			result = append(result, r)
+		}
+
+		var missing []domain.CoinInfo
+		for _, c := range remaining {
+			if _, ok := got[strings.ToUpper(c.Symbol)]; !ok {
+				missing = append(missing, c)
+			}
+		}
+		if len(missing) > 0 {
+			f.logger.Debug("provider returned partial result",
+				slog.String("provider", e.Name),
+				slog.Int("missing", len(missing)))
+		}
+		remaining = missing
+	}
+
+	if len(result) == 0 {
+		if len(failures) == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("all providers failed: %w", errors.Join(failures...))
+	}
+	return result, nil
+}
+
+// fetchEntry — запрос к одному провайдеру с его собственным таймаутом
+func fetchEntry(ctx context.Context, logger *slog.Logger, e Entry, coins []domain.CoinInfo, currency string) ([]domain.Coin, error) {
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	rates, err := e.Provider.FetchRates(ctx, coins, currency)
+	logger.Debug("provider fetch completed",
+		slog.String("provider", e.Name),
+		slog.String("currency", currency),
+		slog.Int("count", len(rates)),
+		slog.Duration("duration", time.Since(started)),
+		slog.Bool("ok", err == nil))
+	return rates, err
+}
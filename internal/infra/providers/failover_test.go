@@ -0,0 +1,128 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	ratesmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/rates/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	btc = domain.CoinInfo{ProviderID: "bitcoin", Symbol: "BTC", Enabled: true}
+	eth = domain.CoinInfo{ProviderID: "ethereum", Symbol: "ETH", Enabled: true}
+	now = time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func TestFailover_FirstProviderOK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	first := ratesmocks.NewMockCryptoProvider(ctrl)
+	second := ratesmocks.NewMockCryptoProvider(ctrl)
+
+	first.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btc}, "usd").
+		Return([]domain.Coin{{Symbol: "BTC", Price: decimal.NewFromInt(100), UpdatedAt: now}}, nil)
+
+	f := NewFailover(slog.Default(), Entry{Name: "a", Provider: first}, Entry{Name: "b", Provider: second})
+	got, err := f.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Source != "a" {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
+
+func TestFailover_FallbackOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	first := ratesmocks.NewMockCryptoProvider(ctrl)
+	second := ratesmocks.NewMockCryptoProvider(ctrl)
+
+	first.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").Return(nil, errors.New("429 Too Many Requests"))
+	second.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btc, eth}, "usd").
+		Return([]domain.Coin{
+			{Symbol: "BTC", Price: decimal.NewFromInt(100), UpdatedAt: now},
+			{Symbol: "ETH", Price: decimal.NewFromInt(10), UpdatedAt: now},
+		}, nil)
+
+	f := NewFailover(slog.Default(), Entry{Name: "a", Provider: first}, Entry{Name: "b", Provider: second})
+	got, err := f.FetchRates(context.Background(), []domain.CoinInfo{btc, eth}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Source != "b" || got[1].Source != "b" {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
+
+func TestFailover_FallbackOnTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	slow := ratesmocks.NewMockCryptoProvider(ctrl)
+	fast := ratesmocks.NewMockCryptoProvider(ctrl)
+
+	slow.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").
+		DoAndReturn(func(ctx context.Context, _ []domain.CoinInfo, _ string) ([]domain.Coin, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	fast.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").
+		Return([]domain.Coin{{Symbol: "BTC", Price: decimal.NewFromInt(100), UpdatedAt: now}}, nil)
+
+	f := NewFailover(slog.Default(),
+		Entry{Name: "slow", Provider: slow, Timeout: 10 * time.Millisecond},
+		Entry{Name: "fast", Provider: fast},
+	)
+	got, err := f.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Source != "fast" {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
+
+func TestFailover_MissingCoinsFromNextProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	first := ratesmocks.NewMockCryptoProvider(ctrl)
+	second := ratesmocks.NewMockCryptoProvider(ctrl)
+
+	first.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btc, eth}, "usd").
+		Return([]domain.Coin{{Symbol: "BTC", Price: decimal.NewFromInt(100), UpdatedAt: now}}, nil)
+	// второй провайдер спрашиваем только о недостающей монете
+	second.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{eth}, "usd").
+		Return([]domain.Coin{{Symbol: "ETH", Price: decimal.NewFromInt(10), UpdatedAt: now}}, nil)
+
+	f := NewFailover(slog.Default(), Entry{Name: "a", Provider: first}, Entry{Name: "b", Provider: second})
+	got, err := f.FetchRates(context.Background(), []domain.CoinInfo{btc, eth}, "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Source != "a" || got[1].Source != "b" {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
+
+func TestFailover_AllFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	first := ratesmocks.NewMockCryptoProvider(ctrl)
+	second := ratesmocks.NewMockCryptoProvider(ctrl)
+
+	errA := errors.New("a down")
+	first.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").Return(nil, errA)
+	second.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").Return(nil, errors.New("b down"))
+
+	f := NewFailover(slog.Default(), Entry{Name: "a", Provider: first}, Entry{Name: "b", Provider: second})
+	_, err := f.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if err == nil || !errors.Is(err, errA) {
+		t.Fatalf("expected joined provider errors, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitError — вызов отклонён лимитером: бюджет запросов исчерпан.
+// errors.Is(err, errs.ErrRateLimited) == true.
+type RateLimitError struct {
+	Provider string
+	RetryIn  time.Duration // через сколько освободится токен
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: %s (retry in %s)", e.Provider, errs.ErrRateLimited, e.RetryIn.Round(time.Millisecond))
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == errs.ErrRateLimited
+}
+
+// RateLimited — CryptoProvider с token-bucket лимитером.
+// Один экземпляр разделяется всеми вызывающими (планировщик загрузки, рассылка, проверка монет в админ API),
+// поэтому лимит действует на процесс целиком. Каталог (LookupCoin) и история (FetchRange)
+// провайдера идут через тот же бюджет, если обёрнутый провайдер их поддерживает.
+type RateLimited struct {
+	name    string
+	next    interfaces.CryptoProvider
+	limiter *rate.Limiter
+	maxWait time.Duration
+	logger  *slog.Logger
+}
+
+// NewRateLimited — perMinute вызовов в минуту, burst — размер корзины (минимум 1).
+// Если токена нет, вызов ждёт в очереди не дольше maxWait, иначе отклоняется с RateLimitError.
+func NewRateLimited(logger *slog.Logger, name string, next interfaces.CryptoProvider, perMinute, burst int, maxWait time.Duration) *RateLimited {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimited{
+		name:    name,
+		next:    next,
+		limiter: rate.NewLimiter(rate.Limit(float64(perMinute)/60), burst),
+		maxWait: maxWait,
+		logger:  logger,
+	}
+}
+
+func (l *RateLimited) FetchRates(ctx context.Context, coins []domain.CoinInfo, currency string) ([]domain.Coin, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	return l.next.FetchRates(ctx, coins, currency)
+}
+
+// LookupCoin — поиск в каталоге провайдера в счёт того же лимита
+func (l *RateLimited) LookupCoin(ctx context.Context, providerID string) (domain.CoinInfo, error) {
+	lookup, ok := l.next.(interfaces.CoinLookup)
+	if !ok {
+		return domain.CoinInfo{}, fmt.Errorf("%s: coin lookup is not supported", l.name)
+	}
+	if err := l.acquire(ctx); err != nil {
+		return domain.CoinInfo{}, err
+	}
+	return lookup.LookupCoin(ctx, providerID)
+}
+
+// FetchRange — исторические цены провайдера в счёт того же лимита
+func (l *RateLimited) FetchRange(ctx context.Context, coin domain.CoinInfo, currency string, from, to time.Time) ([]domain.Coin, error) {
+	history, ok := l.next.(interfaces.HistoryProvider)
+	if !ok {
+		return nil, fmt.Errorf("%s: price history is not supported", l.name)
+	}
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	return history.FetchRange(ctx, coin, currency, from, to)
+}
+
+// acquire — берёт токен, при необходимости ожидая не дольше maxWait и дедлайна контекста
+func (l *RateLimited) acquire(ctx context.Context) error {
+	r := l.limiter.Reserve()
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	limit := l.maxWait
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < limit {
+		limit = time.Until(deadline)
+	}
+	if delay > limit {
+		r.Cancel()
+		l.logger.Warn("provider call rejected by rate limiter",
+			slog.String("provider", l.name),
+			slog.Duration("retry_in", delay))
+		return &RateLimitError{Provider: l.name, RetryIn: delay}
+	}
+
+	l.logger.Debug("provider call queued by rate limiter",
+		slog.String("provider", l.name),
+		slog.Duration("wait", delay))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// CheckBudget — укладывается ли в лимит цикл из calls последовательных вызовов раз в period:
+// все вызовы цикла должны получить токен из корзины без очереди (burst >= calls),
+// а корзина — наполниться к следующему циклу. perMinute <= 0 — лимита нет.
+func CheckBudget(name string, perMinute, burst, calls int, period time.Duration) error {
+	if perMinute <= 0 || calls <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	if burst < calls {
+		return fmt.Errorf("provider %s: rate_limit_burst %d is less than %d calls per fetch cycle (one per currency)", name, burst, calls)
+	}
+	if refill := time.Duration(calls) * time.Minute / time.Duration(perMinute); refill > period {
+		return fmt.Errorf("provider %s: rate_limit_per_minute %d refills %d calls in %s, longer than fetch interval %s",
+			name, perMinute, calls, refill, period)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	coinsmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/coins/mocks"
+	ratesmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/rates/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestRateLimited_RejectsWhenExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	next := ratesmocks.NewMockCryptoProvider(ctrl)
+	next.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").Return([]domain.Coin{{Symbol: "BTC", Price: decimal.NewFromInt(1)}}, nil).Times(1)
+
+	// 1 вызов в минуту, без очереди
+	l := NewRateLimited(slog.Default(), "coingecko", next, 1, 1, 0)
+
+	if _, err := l.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd"); err != nil {
+		t.Fatalf("unexpected error on first call: %v", err)
+	}
+	_, err := l.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd")
+	if !errors.Is(err, derrors.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	var rlErr *RateLimitError
+	if !errors.As(err, &rlErr) || rlErr.Provider != "coingecko" || rlErr.RetryIn <= 0 {
+		t.Fatalf("expected *RateLimitError, got %#v", err)
+	}
+}
+
+func TestRateLimited_QueuesWithinMaxWait(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	next := ratesmocks.NewMockCryptoProvider(ctrl)
+	next.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").Return(nil, nil).Times(2)
+
+	// 1200 в минуту = токен каждые 50мс; второй вызов должен дождаться очереди
+	l := NewRateLimited(slog.Default(), "binance", next, 1200, 1, time.Second)
+
+	started := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := l.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(started); elapsed < 30*time.Millisecond {
+		t.Fatalf("expected second call to wait for a token, elapsed %s", elapsed)
+	}
+}
+
+func TestRateLimited_RespectsContextDeadline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	next := ratesmocks.NewMockCryptoProvider(ctrl)
+	next.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").Return(nil, nil).Times(1)
+
+	l := NewRateLimited(slog.Default(), "coingecko", next, 1, 1, time.Minute)
+	if _, err := l.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// очередь разрешена на минуту, но дедлайн вызывающего короче — отказ сразу
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := l.FetchRates(ctx, []domain.CoinInfo{btc}, "usd"); !errors.Is(err, derrors.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+// catalogProvider — провайдер курсов с каталогом монет (как клиент CoinGecko)
+type catalogProvider struct {
+	*ratesmocks.MockCryptoProvider
+	*coinsmocks.MockCoinLookup
+}
+
+func TestRateLimited_LookupSharesBudgetWithRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	rates := ratesmocks.NewMockCryptoProvider(ctrl)
+	rates.EXPECT().FetchRates(gomock.Any(), gomock.Any(), "usd").Return(nil, nil).Times(1)
+	// каталог не должен вызываться: токен уже израсходован загрузкой курсов
+	lookup := coinsmocks.NewMockCoinLookup(ctrl)
+
+	l := NewRateLimited(slog.Default(), "coingecko", catalogProvider{rates, lookup}, 1, 1, 0)
+	if _, err := l.FetchRates(context.Background(), []domain.CoinInfo{btc}, "usd"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.LookupCoin(context.Background(), "bitcoin"); !errors.Is(err, derrors.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestCheckBudget(t *testing.T) {
+	cases := []struct {
+		name                    string
+		perMinute, burst, calls int
+		period                  time.Duration
+		ok                      bool
+	}{
+		{"cycle fits burst", 10, 4, 4, 5 * time.Minute, true},
+		{"burst too small", 10, 2, 4, 5 * time.Minute, false},
+		{"refill slower than interval", 10, 4, 4, 10 * time.Second, false},
+		{"no limit", 0, 0, 4, time.Second, true},
+	}
+	for _, tc := range cases {
+		err := CheckBudget("coingecko", tc.perMinute, tc.burst, tc.calls, tc.period)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: unexpected result: %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Notifier — отправка сообщения в чат Telegram.
+type Notifier interface {
+	Notify(ctx context.Context, chatID int64, text string) error
+}
+
+// PriceHistory — сохранённые цены на заданные моменты (алерты на изменение за окно).
+type PriceHistory interface {
+	// PricesAt — последняя цена не позже каждого из моментов at (в том же порядке); нулевой Coin — цены нет
+	PricesAt(ctx context.Context, symbol, currency string, at []time.Time) ([]domain.Coin, error)
+}
+
+// AlertStorage — хранилище ценовых алертов (таблица alerts).
+type AlertStorage interface {
+	// CreateAlert — сохранить алерт; возвращает его id
+	CreateAlert(ctx context.Context, a domain.Alert) (int64, error)
+	// ListAlerts — алерты чата по возрастанию id
+	ListAlerts(ctx context.Context, chatID int64) ([]domain.Alert, error)
+	// DeleteAlert — удалить алерт чата; pgx.ErrNoRows — у чата нет алерта с таким id
+	DeleteAlert(ctx context.Context, chatID, id int64) error
+	// AlertsBySymbols — алерты всех чатов на указанные монеты
+	AlertsBySymbols(ctx context.Context, symbols []string) ([]domain.Alert, error)
+	// UpdateAlertState — сохранить состояние алерта; нулевое triggeredAt — время срабатывания не меняется
+	UpdateAlertState(ctx context.Context, id int64, armed bool, triggeredAt time.Time) error
+}
+
+// AlertCommander — интерфейс для команд бота /alert, /movealert, /alerts, /delalert.
+type AlertCommander interface {
+	CreateAlert(ctx context.Context, chatID int64, symbol, currency string, direction domain.AlertDirection, level decimal.Decimal) (domain.Alert, error)
+	CreateMoveAlert(ctx context.Context, chatID int64, symbol, currency string, direction domain.AlertDirection, pct decimal.Decimal, window, cooldown time.Duration) (domain.Alert, error)
+	ListAlerts(ctx context.Context, chatID int64) ([]domain.Alert, error)
+	DeleteAlert(ctx context.Context, chatID, id int64) error
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+)
+
+// HistoryProvider — исторические цены провайдера за период (например, CoinGecko /market_chart/range).
+type HistoryProvider interface {
+	FetchRange(ctx context.Context, coin domain.CoinInfo, currency string, from, to time.Time) ([]domain.Coin, error)
+}
+
+// BackfillStorage — хранилище для загрузки истории: реестр, запись цен и прогресс по началу диапазона.
+type BackfillStorage interface {
+	CoinRegistry
+	SaveCoins(ctx context.Context, items []domain.Coin) error
+	// BackfillProgress — докуда загружена история от from; pgx.ErrNoRows — загрузка от from не начиналась.
+	// Ключ — только начало диапазона: конец (по умолчанию «сейчас») от запуска к запуску меняется.
+	BackfillProgress(ctx context.Context, symbol, currency string, from time.Time) (time.Time, error)
+	SaveBackfillProgress(ctx context.Context, symbol, currency string, from, doneUntil time.Time) error
+}
@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+)
+
+// CoinLookup — поиск монеты в каталоге провайдера (например, CoinGecko /coins/list).
+type CoinLookup interface {
+	LookupCoin(ctx context.Context, providerID string) (domain.CoinInfo, error)
+}
+
+// CoinRegistryStore — чтение и изменение реестра монет (таблица coins).
+type CoinRegistryStore interface {
+	CoinRegistry
+	AddCoin(ctx context.Context, coin domain.CoinInfo) error
+	UpdateCoin(ctx context.Context, symbol string, enabled *bool, name *string) (domain.CoinInfo, error)
+	DeleteCoin(ctx context.Context, symbol string) error
+}
+
+// CoinAdmin — сервис управления отслеживаемыми монетами (админ API).
+type CoinAdmin interface {
+	ListCoins(ctx context.Context) ([]domain.CoinInfo, error)
+	AddCoin(ctx context.Context, providerID, symbol, name string) (domain.CoinInfo, error)
+	UpdateCoin(ctx context.Context, symbol string, enabled *bool, name *string) (domain.CoinInfo, error)
+	RemoveCoin(ctx context.Context, symbol string) error
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+)
+
+// PartitionMaintainer — интерфейс для планировщика и команды обслуживания секций prices.
+type PartitionMaintainer interface {
+	MaintainPartitions(ctx context.Context) (domain.PartitionReport, error)
+}
+
+// PartitionStorage — месячные секции таблицы prices.
+type PartitionStorage interface {
+	// ListPartitions — месячные секции по возрастанию (секция по умолчанию не входит)
+	ListPartitions(ctx context.Context) ([]domain.Partition, error)
+	// CreatePartition — создать секцию p; цены этого периода из секции по умолчанию переносятся в неё
+	CreatePartition(ctx context.Context, p domain.Partition) error
+	// DetachPartition — отсоединить секцию: таблица остаётся, но в выборки prices больше не попадает
+	DetachPartition(ctx context.Context, name string) error
+	// DropPartition — удалить секцию вместе с данными
+	DropPartition(ctx context.Context, name string) error
+}
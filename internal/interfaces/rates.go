@@ -5,11 +5,13 @@ import (
 	"time"
 
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/shopspring/decimal"
 )
 
 // CryptoProvider — внешний источник курсов (например, CoinGecko API).
+// currency — валюта котировки в нижнем регистре (usd, eur).
 type CryptoProvider interface {
-	FetchRates(ctx context.Context) ([]domain.Coin, error)
+	FetchRates(ctx context.Context, coins []domain.CoinInfo, currency string) ([]domain.Coin, error)
 }
 
 // Ingestion — интерфейс для планировщика обновления курсов.
@@ -17,16 +19,49 @@ type Ingestion interface {
 	FetchAndSaveCurrency(ctx context.Context) error
 }
 
+// PriceObserver — получатель цен, сохранённых очередным циклом загрузки (например, проверка алертов).
+// Ошибки обрабатывает сам: цикл загрузки от них не зависит.
+type PriceObserver interface {
+	OnPrices(ctx context.Context, prices []domain.Coin)
+}
+
+// LatestPrices — последние сохранённые цены в валюте currency (опорная цена консенсуса при двух провайдерах).
+type LatestPrices interface {
+	GetAllCoins(ctx context.Context, currency string) ([]domain.Coin, error)
+}
+
+// CoinRegistry — реестр отслеживаемых монет (таблица coins).
+type CoinRegistry interface {
+	ListCoins(ctx context.Context, enabledOnly bool) ([]domain.CoinInfo, error)
+	GetCoinInfo(ctx context.Context, symbol string) (domain.CoinInfo, error)
+}
+
 // Storage — репозиторий для сохранения и выборки курсов из БД.
 type Storage interface {
+	CoinRegistry
 	SaveCoins(ctx context.Context, items []domain.Coin) error
-	GetAllCoins(ctx context.Context) ([]domain.Coin, error)
-	GetCoinBySymbol(ctx context.Context, symbol string) (domain.Coin, error)
-	History(ctx context.Context, symbol string, from, to time.Time) ([]domain.Coin, error)
+	GetAllCoins(ctx context.Context, currency string) ([]domain.Coin, error)
+	GetCoinBySymbol(ctx context.Context, symbol, currency string) (domain.Coin, error)
+	// PriceRange — min/max цены за [from, to] с учётом low/high свёрнутых периодов; нет цен — pgx.ErrNoRows
+	PriceRange(ctx context.Context, symbol, currency string, from, to time.Time) (low, high decimal.Decimal, err error)
+	// PricesAt — последняя цена не позже каждого из моментов at (в том же порядке); нулевой Coin — цены нет
+	PricesAt(ctx context.Context, symbol, currency string, at []time.Time) ([]domain.Coin, error)
+	// HistoryPage — до limit цен за [from, to) строго после after, по возрастанию (timestamp, source) (keyset-пагинация)
+	HistoryPage(ctx context.Context, symbol, currency string, from, to time.Time, after domain.HistoryCursor, limit int) ([]domain.Coin, error)
+	// Candles — OHLC по интервалам длины interval, выровненным от начала эпохи Unix; [from, to)
+	Candles(ctx context.Context, symbol, currency string, interval time.Duration, from, to time.Time) ([]domain.Candle, error)
 }
 
 // Service — сервисный интерфейс для получения актуальных цен и статистики.
+// Пустая currency — валюта по умолчанию (первая из настроенных).
 type Service interface {
-	GetLatest(ctx context.Context) ([]domain.Coin, error)
-	GetLatestBySymbol(ctx context.Context, symbol string, from, to time.Time) (latest domain.Coin, min float64, max float64, pct float64, err error)
+	TrackedCoins(ctx context.Context) ([]domain.CoinInfo, error)
+	Currencies() []string
+	GetLatest(ctx context.Context, currency string) ([]domain.Coin, error)
+	// GetStats — текущая цена, min/max за opts.Window, изменение за opts.Change и блок изменений 1ч/24ч/7д/30д
+	GetStats(ctx context.Context, symbol, currency string, opts domain.StatsOptions) (domain.RateStats, error)
+	// Candles — interval: 1m, 5m, 1h, 1d; пустые from/to — последние 100 свечей
+	Candles(ctx context.Context, symbol, currency, interval string, from, to time.Time) ([]domain.Candle, error)
+	// HistoryPage — сырые цены за [from, to) после курсора after; next — курсор следующей страницы (нулевой, если страниц больше нет)
+	HistoryPage(ctx context.Context, symbol, currency string, from, to time.Time, after domain.HistoryCursor, limit int) (page []domain.Coin, next domain.HistoryCursor, err error)
 }
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// Retention — интерфейс для планировщика хранения старых цен.
+type Retention interface {
+	ApplyRetention(ctx context.Context) error
+}
+
+// RetentionStorage — свёртка и удаление старых цен.
+// Все методы возвращают число удалённых строк исходной таблицы.
+type RetentionStorage interface {
+	// RollupRaw — сырые цены раньше before сворачиваются в часовые агрегаты и удаляются
+	RollupRaw(ctx context.Context, before time.Time) (int64, error)
+	// RollupHourly — часовые агрегаты раньше before сворачиваются в дневные и удаляются
+	RollupHourly(ctx context.Context, before time.Time) (int64, error)
+	// DeleteDaily — удалить дневные агрегаты раньше before
+	DeleteDaily(ctx context.Context, before time.Time) (int64, error)
+}
@@ -3,20 +3,46 @@ package interfaces
 import (
 	"context"
 	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
 )
 
 // Subscriptions — интерфейс для управления подписками
 type Subscriptions interface {
-	FindDue(ctx context.Context, now time.Time) ([]int64, error)
-	MarkSent(ctx context.Context, chatID int64, at time.Time) error
-	MarkEnabled(ctx context.Context, chatID int64, intervalMinutes int) error
+	// FindDue — включённые подписки с NextRun <= now (с часовым поясом чата)
+	FindDue(ctx context.Context, now time.Time) ([]domain.Subscription, error)
+	// GetSubscription — включённая подписка чата; pgx.ErrNoRows, если её нет
+	GetSubscription(ctx context.Context, chatID int64) (domain.Subscription, error)
+	// MarkSent — отметить отправку в at и запланировать следующую на next
+	MarkSent(ctx context.Context, chatID int64, at, next time.Time) error
+	// MarkMissed — рассылка пропущена в тихие часы в at; следующая попытка в next
+	MarkMissed(ctx context.Context, chatID int64, at, next time.Time) error
+	// MarkEnabled — включить/заменить подписку: расписание, монеты, первая рассылка sub.NextRun
+	MarkEnabled(ctx context.Context, sub domain.Subscription) error
 	MarkDisabled(ctx context.Context, chatID int64) error
+	// Reschedule — перенести следующую рассылку (смена часового пояса)
+	Reschedule(ctx context.Context, chatID int64, next time.Time) error
+	// Timezone — часовой пояс чата; "UTC", если не задан
+	Timezone(ctx context.Context, chatID int64) (string, error)
+	SetTimezone(ctx context.Context, chatID int64, tz string) error
+	// QuietHours — тихие часы подписки чата; нулевое значение, если не заданы или подписки нет
+	QuietHours(ctx context.Context, chatID int64) (domain.QuietHours, error)
+	// SetQuietHours — тихие часы хранятся с подпиской; pgx.ErrNoRows — у чата нет подписки
+	SetQuietHours(ctx context.Context, chatID int64, q domain.QuietHours) error
 }
 
-// SubscriptionCommander — интерфейс для команд хендлеров бота (вкл/выкл подписку).
+// SubscriptionCommander — интерфейс для команд хендлеров бота (вкл/выкл подписку, часовой пояс, тихие часы).
 type SubscriptionCommander interface {
-	Enable(ctx context.Context, chatID int64, intervalMinutes int) error
+	// Enable — coins: символы монет рассылки; пустой — все отслеживаемые
+	Enable(ctx context.Context, chatID int64, sched domain.Schedule, coins []string) (domain.Subscription, error)
 	Disable(ctx context.Context, chatID int64) error
+	Timezone(ctx context.Context, chatID int64) (string, error)
+	// SetTimezone — tz: имя IANA (Europe/Moscow); включённая подписка перепланируется
+	SetTimezone(ctx context.Context, chatID int64, tz string) error
+	QuietHours(ctx context.Context, chatID int64) (domain.QuietHours, error)
+	// SetQuietHours — q: тихие часы по часовому поясу чата; нулевое значение — выключить.
+	// ErrSubscriptionNotFound — у чата нет подписки (/startauto)
+	SetQuietHours(ctx context.Context, chatID int64, q domain.QuietHours) error
 }
 
 // SubscriptionDispatcher — интерфейс для планировщика бота (рассылка сообщений).
@@ -3,39 +3,267 @@ package botfmt
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	"github.com/shopspring/decimal"
 )
 
 // FormatRateLine — короткая строка для рассылок /rates
 func FormatRateLine(r domain.Coin) string {
 	return fmt.Sprintf("%s | Текущая цена: %s | Обновлено: %s",
 		r.Symbol,
-		humanPrice(r.Price),
+		priceIn(r.Price, r.Currency),
 		r.UpdatedAt.Format("15:04:05"),
 	)
 }
 
 // FormatRateDetails — подробное сообщение для команды /rates {symbol}
-func FormatRateDetails(latest domain.Coin, min, max, pct float64) string {
-	msg := fmt.Sprintf("Изменение за 1ч: %+.2f%%", pct)
-	// Добавляем пометку, если по отображению это 0.00%
-	if math.Abs(pct) < 0.005 {
+func FormatRateDetails(st domain.RateStats) string {
+	latest := st.Latest
+	window := windowLabel(st.Window)
+	msg := fmt.Sprintf("Изменение за %s: %s", windowLabel(st.Change.Window), formatPct(st.Change.Pct))
+	// Добавляем пометку, если цены на начало окна ещё нет
+	if st.Change.Pct == nil {
 		msg += " (набираем данные для расчёта)"
 	}
 
 	return fmt.Sprintf(
-		"[%s]\nТекущая цена: %s\nМинимальная за 24ч: %s\nМаксимальная за 24ч: %s\n%s\nОбновлено: %s",
+		"[%s]\nТекущая цена: %s\nМинимальная за %s: %s\nМаксимальная за %s: %s\n%s%s%s\nОбновлено: %s",
 		latest.Symbol,
-		humanPrice(latest.Price),
-		humanPrice(min),
-		humanPrice(max),
+		priceIn(latest.Price, latest.Currency),
+		window, priceIn(st.Min, latest.Currency),
+		window, priceIn(st.Max, latest.Currency),
 		msg,
+		formatChanges(st.Changes),
+		formatMarket(latest.Market, latest.Currency),
 		latest.UpdatedAt.Format("15:04:05"),
 	)
 }
 
-// humanPrice — форматирование числа с двумя знаками после запятой.
-func humanPrice(v float64) string {
-	return fmt.Sprintf("%.2f", v)
+// formatChanges — блок изменений за стандартные окна: 1ч +0.12% | 24ч -1.05% | ...
+func formatChanges(changes []domain.Change) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		parts = append(parts, windowLabel(ch.Window)+" "+formatPct(ch.Pct))
+	}
+	return "\nИзменения: " + strings.Join(parts, " | ")
+}
+
+// formatPct — процент со знаком; nil — нет данных
+func formatPct(pct *float64) string {
+	if pct == nil {
+		return "н/д"
+	}
+	return fmt.Sprintf("%+.2f%%", *pct)
+}
+
+// windowLabel — окно по-русски: 15мин, 1ч, 7д
+func windowLabel(d time.Duration) string {
+	w := utils.FormatWindow(d)
+	switch {
+	case strings.HasSuffix(w, "d"):
+		return strings.TrimSuffix(w, "d") + "д"
+	case strings.HasSuffix(w, "h"):
+		return strings.TrimSuffix(w, "h") + "ч"
+	case strings.HasSuffix(w, "m"):
+		return strings.TrimSuffix(w, "m") + "мин"
+	default:
+		return w
+	}
+}
+
+// formatMarket — рыночные показатели провайдера (только те, что есть)
+func formatMarket(m domain.MarketData, currency string) string {
+	var b strings.Builder
+	if m.Change24hPct != nil {
+		fmt.Fprintf(&b, "\nИзменение за 24ч: %+.2f%%", *m.Change24hPct)
+	}
+	if m.High24h != nil && m.Low24h != nil {
+		fmt.Fprintf(&b, "\nДиапазон 24ч (биржи): %s – %s", priceIn(*m.Low24h, currency), priceIn(*m.High24h, currency))
+	}
+	if m.Volume24h != nil {
+		fmt.Fprintf(&b, "\nОбъём за 24ч: %s", humanAmount(*m.Volume24h))
+	}
+	if m.MarketCap != nil {
+		fmt.Fprintf(&b, "\nКапитализация: %s", humanAmount(*m.MarketCap))
+	}
+	if m.CirculatingSupply != nil {
+		fmt.Fprintf(&b, "\nВ обращении: %s", humanAmount(*m.CirculatingSupply))
+	}
+	return b.String()
+}
+
+// FormatStaleMark — пометка для цены, которая давно не обновлялась
+func FormatStaleMark(age time.Duration) string {
+	return fmt.Sprintf(" (устарело: %d мин.)", int(age.Minutes()))
+}
+
+// priceSigDigits — значащих цифр для цен меньше единицы (SHIB 0.00001234, а не 0.00)
+const priceSigDigits = 4
+
+// humanPrice — цены от 1 — с двумя знаками после запятой,
+// меньше 1 — с priceSigDigits значащими цифрами.
+func humanPrice(v decimal.Decimal) string {
+	a := v.Abs()
+	if a.IsZero() || a.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return v.StringFixed(2)
+	}
+	// позиция первой значащей цифры после запятой: 0.00001234 → 5
+	lead := int32(-math.Floor(math.Log10(a.InexactFloat64())))
+	r := v.Round(lead - 1 + priceSigDigits)
+	if str := r.String(); hasMinDecimals(str, 2) {
+		return str
+	}
+	return r.StringFixed(2)
+}
+
+// hasMinDecimals — в записи числа не меньше n знаков после запятой
+func hasMinDecimals(s string, n int) bool {
+	i := strings.IndexByte(s, '.')
+	return i >= 0 && len(s)-i-1 >= n
+}
+
+// priceIn — цена с кодом валюты котировки (61234.56 USD)
+func priceIn(v decimal.Decimal, currency string) string {
+	if currency == "" {
+		return humanPrice(v)
+	}
+	return humanPrice(v) + " " + strings.ToUpper(currency)
+}
+
+// humanAmount — крупные величины (объём, капитализация) в виде 1.23 млрд.
+func humanAmount(v float64) string {
+	switch a := math.Abs(v); {
+	case a >= 1e12:
+		return fmt.Sprintf("%.2f трлн", v/1e12)
+	case a >= 1e9:
+		return fmt.Sprintf("%.2f млрд", v/1e9)
+	case a >= 1e6:
+		return fmt.Sprintf("%.2f млн", v/1e6)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+// FormatAlertLine — строка списка /alerts: #12 BTC > 70000.00 USD, #13 ETH ±5% за 1ч
+func FormatAlertLine(a domain.Alert) string {
+	if a.Kind == domain.AlertMove {
+		s := fmt.Sprintf("#%d %s %s%s%% за %s", a.ID, a.Symbol, moveSign(a.Direction), a.Level.String(), windowLabel(a.Window))
+		if a.Currency != "" {
+			s += " (" + strings.ToUpper(a.Currency) + ")"
+		}
+		s += ", пауза " + windowLabel(a.Cooldown)
+		if !a.TriggeredAt.IsZero() {
+			s += ", сработал " + a.TriggeredAt.Format("02.01 15:04")
+		}
+		return s
+	}
+	s := fmt.Sprintf("#%d %s %s %s", a.ID, a.Symbol, alertSign(a.Direction), priceIn(a.Level, a.Currency))
+	if !a.TriggeredAt.IsZero() {
+		s += ", сработал " + a.TriggeredAt.Format("02.01 15:04")
+	}
+	if !a.Armed {
+		s += " (ждёт возврата цены за уровень)"
+	}
+	return s
+}
+
+// FormatAlertTriggered — уведомление о пересечении уровня
+func FormatAlertTriggered(a domain.Alert, price domain.Coin) string {
+	verb := "поднялся выше"
+	if a.Direction == domain.AlertBelow {
+		verb = "опустился ниже"
+	}
+	return fmt.Sprintf("Алерт #%d: %s %s %s\nТекущая цена: %s\nОбновлено: %s",
+		a.ID, a.Symbol, verb, priceIn(a.Level, a.Currency),
+		priceIn(price.Price, price.Currency),
+		price.UpdatedAt.Format("15:04:05"),
+	)
+}
+
+// FormatMoveAlertTriggered — уведомление об изменении цены за окно
+func FormatMoveAlertTriggered(a domain.Alert, price domain.Coin, pct decimal.Decimal) string {
+	p := pct.InexactFloat64()
+	return fmt.Sprintf("Алерт #%d: %s изменился на %s за %s\nТекущая цена: %s\nОбновлено: %s",
+		a.ID, a.Symbol, formatPct(&p), windowLabel(a.Window),
+		priceIn(price.Price, price.Currency),
+		price.UpdatedAt.Format("15:04:05"),
+	)
+}
+
+// moveSign — направление алерта на изменение: +5% рост, -5% падение, ±5% любое
+func moveSign(d domain.AlertDirection) string {
+	switch d {
+	case domain.AlertAbove:
+		return "+"
+	case domain.AlertBelow:
+		return "-"
+	default:
+		return "±"
+	}
+}
+
+func alertSign(d domain.AlertDirection) string {
+	if d == domain.AlertBelow {
+		return "<"
+	}
+	return ">"
+}
+
+// FormatSchedule — расписание авторассылки: «каждые 10 мин.», «каждый день в 09:00», «по будням в 18:00», «по cron 0 */4 * * *»
+func FormatSchedule(s domain.Schedule) string {
+	switch s.Kind {
+	case domain.ScheduleInterval:
+		return fmt.Sprintf("каждые %d мин.", int(s.Interval/time.Minute))
+	case domain.ScheduleDaily:
+		return "каждый день в " + s.Spec
+	}
+	// /startauto weekdays 18:00 сохраняется как «0 18 * * 1-5»
+	f := strings.Fields(s.Spec)
+	if len(f) == 5 && f[2] == "*" && f[3] == "*" && f[4] == "1-5" {
+		m, errM := strconv.Atoi(f[0])
+		h, errH := strconv.Atoi(f[1])
+		if errM == nil && errH == nil {
+			return fmt.Sprintf("по будням в %02d:%02d", h, m)
+		}
+	}
+	return "по cron " + s.Spec
+}
+
+// FormatQuietHours — тихие часы для ответа /quiet: «23:00–08:00, со сводкой»
+func FormatQuietHours(q domain.QuietHours) string {
+	s := clock(q.Start) + "–" + clock(q.End)
+	if q.Summary {
+		s += ", со сводкой"
+	}
+	return s
+}
+
+// FormatQuietSummary — сводка первой рассылки после тихих часов: изменение каждой монеты с начала тишины.
+// since — первая пропущенная рассылка по времени чата; was[i] — цена монеты now[i] на этот момент (нулевая — нет данных).
+func FormatQuietSummary(since time.Time, was, now []domain.Coin) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Сводка за тихие часы (рассылки не отправлялись с %s):", since.Format("02.01 15:04"))
+	for i, cur := range now {
+		b.WriteString("\n" + cur.Symbol + ": ")
+		if i >= len(was) || !was[i].Price.IsPositive() {
+			b.WriteString("н/д")
+			continue
+		}
+		pct := cur.Price.Sub(was[i].Price).Div(was[i].Price).Mul(decimal.NewFromInt(100)).InexactFloat64()
+		fmt.Fprintf(&b, "%s → %s (%s)", priceIn(was[i].Price, cur.Currency), priceIn(cur.Price, cur.Currency), formatPct(&pct))
+	}
+	return b.String()
+}
+
+// clock — смещение от полуночи в виде 08:00
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
 }
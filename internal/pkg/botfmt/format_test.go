@@ -0,0 +1,114 @@
+package botfmt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestHumanPrice(t *testing.T) {
+	cases := map[string]string{
+		"61234.5678":   "61234.57",
+		"1":            "1.00",
+		"0.5":          "0.50",
+		"0.1234567":    "0.1235",
+		"0.00001234":   "0.00001234",
+		"0.0000123456": "0.00001235",
+		"0.99999":      "1.00",
+		"0":            "0.00",
+	}
+	for in, want := range cases {
+		if got := humanPrice(decimal.RequireFromString(in)); got != want {
+			t.Errorf("humanPrice(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestPriceIn(t *testing.T) {
+	if got := priceIn(decimal.RequireFromString("0.04123456"), "btc"); got != "0.04123 BTC" {
+		t.Fatalf("unexpected btc price: %s", got)
+	}
+}
+
+func TestFormatRateDetails_Windows(t *testing.T) {
+	pct := 1.5
+	st := domain.RateStats{
+		Latest: domain.Coin{Symbol: "BTC", Price: decimal.NewFromInt(105), Currency: "usd"},
+		Window: 7 * 24 * time.Hour,
+		Min:    decimal.NewFromInt(90),
+		Max:    decimal.NewFromInt(110),
+		Change: domain.Change{Window: 24 * time.Hour, Pct: &pct},
+		Changes: []domain.Change{
+			{Window: time.Hour, Pct: &pct},
+			{Window: 30 * 24 * time.Hour},
+		},
+	}
+	got := FormatRateDetails(st)
+	for _, want := range []string{
+		"Минимальная за 7д: 90.00 USD",
+		"Изменение за 24ч: +1.50%",
+		"Изменения: 1ч +1.50% | 30д н/д",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatAlertLine(t *testing.T) {
+	a := domain.Alert{ID: 3, Symbol: "ETH", Currency: "eur", Direction: domain.AlertBelow,
+		Level: decimal.NewFromInt(3000), TriggeredAt: time.Date(2025, 9, 16, 8, 5, 0, 0, time.UTC)}
+	if got, want := FormatAlertLine(a), "#3 ETH < 3000.00 EUR, сработал 16.09 08:05 (ждёт возврата цены за уровень)"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatAlertLine_Move(t *testing.T) {
+	a := domain.Alert{ID: 5, Kind: domain.AlertMove, Symbol: "BTC", Currency: "usd", Direction: domain.AlertBelow,
+		Level: decimal.RequireFromString("2.5"), Window: time.Hour, Cooldown: 2 * time.Hour, Armed: true}
+	if got, want := FormatAlertLine(a), "#5 BTC -2.5% за 1ч (USD), пауза 2ч"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatSchedule(t *testing.T) {
+	cases := []struct {
+		s    domain.Schedule
+		want string
+	}{
+		{domain.Schedule{Kind: domain.ScheduleInterval, Interval: 15 * time.Minute}, "каждые 15 мин."},
+		{domain.Schedule{Kind: domain.ScheduleDaily, Spec: "09:00"}, "каждый день в 09:00"},
+		{domain.Schedule{Kind: domain.ScheduleCron, Spec: "5 8 * * 1-5"}, "по будням в 08:05"},
+		{domain.Schedule{Kind: domain.ScheduleCron, Spec: "0 */4 * * *"}, "по cron 0 */4 * * *"},
+	}
+	for _, tc := range cases {
+		if got := FormatSchedule(tc.s); got != tc.want {
+			t.Errorf("got %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestFormatQuietHours(t *testing.T) {
+	q := domain.QuietHours{Start: 23 * time.Hour, End: 8*time.Hour + 30*time.Minute, Summary: true}
+	if got, want := FormatQuietHours(q), "23:00–08:30, со сводкой"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatQuietSummary(t *testing.T) {
+	since := time.Date(2025, 9, 16, 23, 10, 0, 0, time.UTC)
+	was := []domain.Coin{{Price: decimal.NewFromInt(100)}, {}}
+	now := []domain.Coin{
+		{Symbol: "BTC", Currency: "usd", Price: decimal.NewFromInt(105)},
+		{Symbol: "SOL", Currency: "usd", Price: decimal.NewFromInt(150)},
+	}
+	want := "Сводка за тихие часы (рассылки не отправлялись с 16.09 23:10):\n" +
+		"BTC: 100.00 USD → 105.00 USD (+5.00%)\n" +
+		"SOL: н/д"
+	if got := FormatQuietSummary(since, was, now); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
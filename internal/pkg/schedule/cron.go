@@ -0,0 +1,184 @@
+package schedule
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSpec — разобранное cron-выражение «минута час день месяц день_недели».
+// Каждое поле — битовая маска допустимых значений.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	// domAny, dowAny — поле задано как *: по правилам cron при ограничении обоих полей
+	// день подходит, если совпало любое из них
+	domAny, dowAny bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{min: 0, max: 59}
+	hourField   = cronField{min: 0, max: 23}
+	domField    = cronField{min: 1, max: 31}
+	monthField  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 0 и 7 — воскресенье
+	dowField = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// parseCron — стандартный синтаксис: *, списки (1,15), диапазоны (1-5), шаги (*/15, 0-30/5), имена (mon-fri, jan)
+func parseCron(expr string) (cronSpec, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("cron: expected 5 fields, got %d", len(fields))
+	}
+	var (
+		c   cronSpec
+		err error
+	)
+	if c.minute, err = minuteField.parse(fields[0]); err != nil {
+		return cronSpec{}, err
+	}
+	if c.hour, err = hourField.parse(fields[1]); err != nil {
+		return cronSpec{}, err
+	}
+	if c.dom, err = domField.parse(fields[2]); err != nil {
+		return cronSpec{}, err
+	}
+	if c.month, err = monthField.parse(fields[3]); err != nil {
+		return cronSpec{}, err
+	}
+	if c.dow, err = dowField.parse(fields[4]); err != nil {
+		return cronSpec{}, err
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.domAny, c.dowAny = fields[2] == "*", fields[4] == "*"
+	return c, nil
+}
+
+func (f cronField) parse(s string) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("cron: invalid step in %q", part)
+			}
+			step = n
+		}
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(to); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max // 5/15 — с 5 до конца диапазона
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("cron: invalid range %q", rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << v
+		}
+	}
+	return mask, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[s]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("cron: value %q out of range %d-%d", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// cronHorizon — сколько дней вперёд искать срабатывание (31 февраля не наступит никогда)
+const cronHorizon = 366 * 5
+
+// next — первое срабатывание строго после after по местному времени loc; нулевое — не найдено.
+// Время перебирается по календарным датам и часам loc, поэтому переходы на летнее/зимнее время
+// не сдвигают расписание: «каждый день в 09:00» остаётся 09:00 местного времени.
+// Несуществующее местное время (перевод часов вперёд) сдвигается на величину перехода,
+// повторяющееся (перевод назад) срабатывает один раз.
+func (c cronSpec) next(after time.Time, loc *time.Location) time.Time {
+	local := after.In(loc)
+	y, m, d := local.Date()
+	for i := 0; i < cronHorizon; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, loc)
+		if !c.matchDay(day) {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if c.hour&(1<<h) == 0 {
+				continue
+			}
+			for mask := c.minute; mask != 0; mask &= mask - 1 {
+				mi := bits.TrailingZeros64(mask)
+				t := localTime(day, h, mi, loc)
+				if t.After(after) {
+					return t
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (c cronSpec) matchDay(day time.Time) bool {
+	if c.month&(1<<int(day.Month())) == 0 {
+		return false
+	}
+	domOK := c.dom&(1<<day.Day()) != 0
+	dowOK := c.dow&(1<<int(day.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowOK
+	case c.dowAny:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+// localTime — момент h:mi местного времени дня day. Если такого времени нет (перевод часов вперёд),
+// оно сдвигается на величину перехода: 02:30 при переводе 02:00→03:00 — это 03:30.
+func localTime(day time.Time, h, mi int, loc *time.Location) time.Time {
+	t := time.Date(day.Year(), day.Month(), day.Day(), h, mi, 0, 0, loc)
+	if t.Hour() == h && t.Minute() == mi {
+		return t
+	}
+	// time.Date нормализует несуществующее время по одному из смещений перехода — берём более позднее
+	_, off := t.Zone()
+	wall := time.Date(day.Year(), day.Month(), day.Day(), h, mi, 0, 0, time.UTC)
+	if shifted := wall.Add(-time.Duration(off) * time.Second).In(loc); shifted.After(t) {
+		return shifted
+	}
+	return t
+}
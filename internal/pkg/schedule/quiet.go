@@ -0,0 +1,50 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+)
+
+// ValidateQuiet — проверка тихих часов: границы в пределах суток с точностью до минуты, начало не равно концу
+func ValidateQuiet(q domain.QuietHours) error {
+	for _, d := range []time.Duration{q.Start, q.End} {
+		if d < 0 || d >= 24*time.Hour || d%time.Minute != 0 {
+			return errors.New("schedule: quiet hours bounds must be HH:MM")
+		}
+	}
+	if !q.Enabled() {
+		return errors.New("schedule: quiet hours start must differ from end")
+	}
+	return nil
+}
+
+// QuietUntil — если t попадает в тихие часы q по местному времени loc, возвращает их окончание (в UTC),
+// иначе нулевое время.
+func QuietUntil(q domain.QuietHours, t time.Time, loc *time.Location) time.Time {
+	if !q.Enabled() {
+		return time.Time{}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	now := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	var active bool
+	if q.Start < q.End {
+		active = now >= q.Start && now < q.End
+	} else {
+		active = now >= q.Start || now < q.End
+	}
+	if !active {
+		return time.Time{}
+	}
+	if now >= q.End {
+		// 23:00-08:00, сейчас 23:30 — тишина до 08:00 завтра
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	}
+	end := localTime(day, int(q.End/time.Hour), int(q.End%time.Hour/time.Minute), loc)
+	return end.UTC()
+}
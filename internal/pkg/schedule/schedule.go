@@ -0,0 +1,78 @@
+// Package schedule — расписания авторассылки: интервал, ежедневно в заданное время, cron.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+)
+
+// dailyLayout — время ежедневной рассылки
+const dailyLayout = "15:04"
+
+// Validate — проверка расписания
+func Validate(s domain.Schedule) error {
+	switch s.Kind {
+	case domain.ScheduleInterval:
+		if s.Interval < time.Minute || s.Interval%time.Minute != 0 {
+			return errors.New("schedule: interval must be a positive whole number of minutes")
+		}
+		return nil
+	case domain.ScheduleDaily:
+		_, err := dailyCron(s.Spec)
+		return err
+	case domain.ScheduleCron:
+		c, err := parseCron(s.Spec)
+		if err != nil {
+			return err
+		}
+		if c.next(time.Now(), time.UTC).IsZero() {
+			return fmt.Errorf("cron: %q never fires", s.Spec)
+		}
+		return nil
+	}
+	return fmt.Errorf("schedule: unknown kind %q", s.Kind)
+}
+
+// Next — следующая рассылка строго после after (в UTC); daily и cron считаются по местному времени loc.
+func Next(s domain.Schedule, after time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var (
+		c   cronSpec
+		err error
+	)
+	switch s.Kind {
+	case domain.ScheduleInterval:
+		if err := Validate(s); err != nil {
+			return time.Time{}, err
+		}
+		return after.Add(s.Interval), nil
+	case domain.ScheduleDaily:
+		c, err = dailyCron(s.Spec)
+	case domain.ScheduleCron:
+		c, err = parseCron(s.Spec)
+	default:
+		err = fmt.Errorf("schedule: unknown kind %q", s.Kind)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := c.next(after, loc)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("schedule: %q never fires", s.Spec)
+	}
+	return next.UTC(), nil
+}
+
+// dailyCron — "09:00" → «0 9 * * *»
+func dailyCron(spec string) (cronSpec, error) {
+	t, err := time.Parse(dailyLayout, spec)
+	if err != nil {
+		return cronSpec{}, fmt.Errorf("schedule: daily time must be HH:MM, got %q", spec)
+	}
+	return parseCron(fmt.Sprintf("%d %d * * *", t.Minute(), t.Hour()))
+}
@@ -0,0 +1,185 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestNext_Cron(t *testing.T) {
+	after := time.Date(2025, 9, 16, 12, 7, 0, 0, time.UTC) // вторник
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 9, 16, 12, 15, 0, 0, time.UTC)},
+		{"0 18 * * 1-5", time.Date(2025, 9, 16, 18, 0, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2025, 9, 20, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2025, 9, 21, 9, 0, 0, 0, time.UTC)},
+		{"30 8 1 jan *", time.Date(2026, 1, 1, 8, 30, 0, 0, time.UTC)},
+		// день месяца и день недели — срабатывает любой из них
+		{"0 0 20 * mon", time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC)},
+		{"7 12 * * *", time.Date(2025, 9, 17, 12, 7, 0, 0, time.UTC)}, // строго после after
+	}
+	for _, tc := range cases {
+		got, err := Next(domain.Schedule{Kind: domain.ScheduleCron, Spec: tc.spec}, after, time.UTC)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.spec, err)
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("%s: got %s, want %s", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestNext_DailyInTimezone(t *testing.T) {
+	msk := mustLoad(t, "Europe/Moscow")
+	after := time.Date(2025, 9, 16, 7, 0, 0, 0, time.UTC) // 10:00 по Москве
+	got, err := Next(domain.Schedule{Kind: domain.ScheduleDaily, Spec: "09:00"}, after, msk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 9, 17, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("got %s, want %s", got.UTC(), want)
+	}
+}
+
+func TestNext_DailyAcrossDST(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	daily := domain.Schedule{Kind: domain.ScheduleDaily, Spec: "09:00"}
+
+	// 2025-03-09 — переход на летнее время: 09:00 остаётся 09:00 местного, а не 10:00
+	got, err := Next(daily, time.Date(2025, 3, 8, 9, 0, 0, 0, ny), ny)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 3, 9, 13, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("spring: got %s, want %s", got.UTC(), want)
+	}
+	// 2025-11-02 — возврат на зимнее время
+	got, err = Next(daily, time.Date(2025, 11, 1, 9, 0, 0, 0, ny), ny)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 11, 2, 14, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("fall: got %s, want %s", got.UTC(), want)
+	}
+}
+
+func TestNext_DSTGapAndOverlap(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	at := domain.Schedule{Kind: domain.ScheduleDaily, Spec: "02:30"}
+
+	// 02:30 9 марта не существует — рассылка сдвигается на 03:30 EDT, а не пропускает день
+	got, err := Next(at, time.Date(2025, 3, 8, 12, 0, 0, 0, ny), ny)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("gap: got %s, want %s", got.UTC(), want)
+	}
+
+	// 01:30 2 ноября наступает дважды — рассылка одна
+	at.Spec = "01:30"
+	first, err := Next(at, time.Date(2025, 11, 1, 12, 0, 0, 0, ny), ny)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := Next(at, first, ny)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 11, 3, 6, 30, 0, 0, time.UTC); !second.Equal(want) {
+		t.Fatalf("overlap: first %s, second %s, want %s", first.UTC(), second.UTC(), want)
+	}
+}
+
+func TestNext_Interval(t *testing.T) {
+	after := time.Date(2025, 9, 16, 12, 0, 0, 0, time.UTC)
+	got, err := Next(domain.Schedule{Kind: domain.ScheduleInterval, Interval: 10 * time.Minute}, after, nil)
+	if err != nil || !got.Equal(after.Add(10*time.Minute)) {
+		t.Fatalf("unexpected next: %s err=%v", got, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []domain.Schedule{
+		{Kind: domain.ScheduleInterval},
+		{Kind: domain.ScheduleInterval, Interval: 90 * time.Second},
+		{Kind: domain.ScheduleDaily, Spec: "25:00"},
+		{Kind: domain.ScheduleDaily, Spec: "9"},
+		{Kind: domain.ScheduleCron, Spec: "* * * *"},
+		{Kind: domain.ScheduleCron, Spec: "60 * * * *"},
+		{Kind: domain.ScheduleCron, Spec: "0 9 * * 1-"},
+		{Kind: domain.ScheduleCron, Spec: "*/0 * * * *"},
+		{Kind: domain.ScheduleCron, Spec: "0 0 31 2 *"},
+		{Kind: "weekly", Spec: "mon"},
+	}
+	for _, s := range bad {
+		if err := Validate(s); err == nil {
+			t.Errorf("expected error for %+v", s)
+		}
+	}
+	good := []domain.Schedule{
+		{Kind: domain.ScheduleInterval, Interval: time.Hour},
+		{Kind: domain.ScheduleDaily, Spec: "09:00"},
+		{Kind: domain.ScheduleCron, Spec: "0 9-18/3 * * mon-fri"},
+		{Kind: domain.ScheduleCron, Spec: "0 0 29 2 *"},
+	}
+	for _, s := range good {
+		if err := Validate(s); err != nil {
+			t.Errorf("unexpected error for %+v: %v", s, err)
+		}
+	}
+}
+
+func TestQuietUntil(t *testing.T) {
+	msk := mustLoad(t, "Europe/Moscow")
+	night := domain.QuietHours{Start: 23 * time.Hour, End: 8 * time.Hour}
+	day := domain.QuietHours{Start: 13 * time.Hour, End: 14*time.Hour + 30*time.Minute}
+	cases := []struct {
+		name string
+		q    domain.QuietHours
+		at   time.Time // по Москве
+		want time.Time // UTC; нулевое — не тихие часы
+	}{
+		{"before midnight", night, time.Date(2025, 9, 16, 23, 30, 0, 0, msk), time.Date(2025, 9, 17, 5, 0, 0, 0, time.UTC)},
+		{"after midnight", night, time.Date(2025, 9, 17, 3, 0, 0, 0, msk), time.Date(2025, 9, 17, 5, 0, 0, 0, time.UTC)},
+		{"end is exclusive", night, time.Date(2025, 9, 17, 8, 0, 0, 0, msk), time.Time{}},
+		{"daytime", night, time.Date(2025, 9, 17, 12, 0, 0, 0, msk), time.Time{}},
+		{"same-day window", day, time.Date(2025, 9, 17, 13, 0, 0, 0, msk), time.Date(2025, 9, 17, 11, 30, 0, 0, time.UTC)},
+		{"disabled", domain.QuietHours{}, time.Date(2025, 9, 17, 3, 0, 0, 0, msk), time.Time{}},
+	}
+	for _, tc := range cases {
+		if got := QuietUntil(tc.q, tc.at, msk); !got.Equal(tc.want) {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestValidateQuiet(t *testing.T) {
+	for _, q := range []domain.QuietHours{
+		{},
+		{Start: 8 * time.Hour, End: 8 * time.Hour},
+		{Start: 23 * time.Hour, End: 24 * time.Hour},
+		{Start: time.Hour, End: 2*time.Hour + time.Second},
+	} {
+		if err := ValidateQuiet(q); err == nil {
+			t.Errorf("expected error for %+v", q)
+		}
+	}
+	if err := ValidateQuiet(domain.QuietHours{Start: 23 * time.Hour, End: 8 * time.Hour}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	cases := map[string]time.Duration{
+		"15m": 15 * time.Minute,
+		"1h":  time.Hour,
+		"24H": 24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+	}
+	for in, want := range cases {
+		got, err := ParseWindow(in)
+		if err != nil || got != want {
+			t.Errorf("ParseWindow(%q) = %v, %v; want %v", in, got, err, want)
+		}
+		if back := FormatWindow(got); back != FormatWindow(want) {
+			t.Errorf("FormatWindow(%v) = %s", got, back)
+		}
+	}
+	for _, in := range []string{"", "0d", "-1h", "week", "1.5d"} {
+		if _, err := ParseWindow(in); err == nil {
+			t.Errorf("ParseWindow(%q): expected error", in)
+		}
+	}
+	if got := FormatWindow(24 * time.Hour); got != "24h" {
+		t.Errorf("FormatWindow(24h) = %s", got)
+	}
+}
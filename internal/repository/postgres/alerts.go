@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AlertRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAlertRepo(db *pgxpool.Pool) *AlertRepo {
+	return &AlertRepo{db: db}
+}
+
+const alertColumns = `id, chat_id, kind, coin_symbol, currency, direction, level,
+	window_seconds, cooldown_seconds, armed, triggered_at, created_at`
+
+// CreateAlert — сохранить алерт; возвращает его id.
+func (r *AlertRepo) CreateAlert(ctx context.Context, a domain.Alert) (int64, error) {
+	const query = `
+		INSERT INTO alerts (chat_id, kind, coin_symbol, currency, direction, level, window_seconds, cooldown_seconds, armed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`
+	var window *int64
+	if a.Window > 0 {
+		seconds := int64(a.Window / time.Second)
+		window = &seconds
+	}
+	var id int64
+	err := r.db.QueryRow(ctx, query, a.ChatID, string(a.Kind), a.Symbol, a.Currency, string(a.Direction), a.Level,
+		window, int64(a.Cooldown/time.Second), a.Armed).Scan(&id)
+	return id, err
+}
+
+// ListAlerts — алерты чата по возрастанию id.
+func (r *AlertRepo) ListAlerts(ctx context.Context, chatID int64) ([]domain.Alert, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+alertColumns+` FROM alerts WHERE chat_id = $1 ORDER BY id`, chatID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAlerts(rows)
+}
+
+// DeleteAlert — удалить алерт чата. Если у чата нет такого алерта — возвращает pgx.ErrNoRows.
+func (r *AlertRepo) DeleteAlert(ctx context.Context, chatID, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM alerts WHERE id = $1 AND chat_id = $2`, id, chatID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// AlertsBySymbols — алерты всех чатов на указанные монеты.
+func (r *AlertRepo) AlertsBySymbols(ctx context.Context, symbols []string) ([]domain.Alert, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+alertColumns+` FROM alerts WHERE coin_symbol = ANY($1) ORDER BY id`, symbols)
+	if err != nil {
+		return nil, err
+	}
+	return scanAlerts(rows)
+}
+
+// UpdateAlertState — сохранить состояние алерта; нулевое triggeredAt не меняет время срабатывания.
+func (r *AlertRepo) UpdateAlertState(ctx context.Context, id int64, armed bool, triggeredAt time.Time) error {
+	const query = `UPDATE alerts SET armed = $2, triggered_at = COALESCE($3, triggered_at) WHERE id = $1`
+	var at *time.Time
+	if !triggeredAt.IsZero() {
+		at = &triggeredAt
+	}
+	_, err := r.db.Exec(ctx, query, id, armed, at)
+	return err
+}
+
+func scanAlerts(rows pgx.Rows) ([]domain.Alert, error) {
+	defer rows.Close()
+
+	var out []domain.Alert
+	for rows.Next() {
+		var (
+			a               domain.Alert
+			kind, direction string
+			window          *int64
+			cooldown        int64
+			triggeredAt     *time.Time
+		)
+		if err := rows.Scan(&a.ID, &a.ChatID, &kind, &a.Symbol, &a.Currency, &direction, &a.Level,
+			&window, &cooldown, &a.Armed, &triggeredAt, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.Kind = domain.AlertKind(kind)
+		a.Direction = domain.AlertDirection(direction)
+		if window != nil {
+			a.Window = time.Duration(*window) * time.Second
+		}
+		a.Cooldown = time.Duration(cooldown) * time.Second
+		if triggeredAt != nil {
+			a.TriggeredAt = *triggeredAt
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
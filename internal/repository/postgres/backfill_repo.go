@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+// BackfillProgress — докуда загружена история монеты в валюте currency от момента from.
+func (r *CoinRepo) BackfillProgress(ctx context.Context, symbol, currency string, from time.Time) (time.Time, error) {
+	const query = `
+		SELECT done_until
+		FROM backfill_progress
+		WHERE coin_symbol = $1 AND currency = $2 AND range_from = $3
+	`
+	var doneUntil time.Time
+	err := r.db.QueryRow(ctx, query, symbol, currency, from).Scan(&doneUntil)
+	return doneUntil, err
+}
+
+// SaveBackfillProgress — отметить, что история от from загружена до doneUntil.
+// Прогресс только растёт: запуск с более ранним to не откатывает то, что уже загружено.
+func (r *CoinRepo) SaveBackfillProgress(ctx context.Context, symbol, currency string, from, doneUntil time.Time) error {
+	const query = `
+		INSERT INTO backfill_progress (coin_symbol, currency, range_from, done_until)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (coin_symbol, currency, range_from)
+		DO UPDATE SET done_until = GREATEST(backfill_progress.done_until, EXCLUDED.done_until), updated_at = now()
+	`
+	_, err := r.db.Exec(ctx, query, symbol, currency, from, doneUntil)
+	return err
+}
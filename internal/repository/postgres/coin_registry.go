@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// ListCoins — получить монеты из реестра (при enabledOnly — только включённые).
+func (r *CoinRepo) ListCoins(ctx context.Context, enabledOnly bool) ([]domain.CoinInfo, error) {
+	const query = `
+		SELECT provider_id, symbol, name, enabled
+		FROM coins
+		WHERE enabled OR NOT $1
+		ORDER BY symbol
+	`
+
+	rows, err := r.db.Query(ctx, query, enabledOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.CoinInfo
+	for rows.Next() {
+		var c domain.CoinInfo
+		if err := rows.Scan(&c.ProviderID, &c.Symbol, &c.Name, &c.Enabled); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return out, nil
+}
+
+// GetCoinInfo — получить запись реестра по символу монеты.
+func (r *CoinRepo) GetCoinInfo(ctx context.Context, symbol string) (domain.CoinInfo, error) {
+	const query = `
+		SELECT provider_id, symbol, name, enabled
+		FROM coins
+		WHERE symbol = $1
+	`
+	var c domain.CoinInfo
+	err := r.db.QueryRow(ctx, query, symbol).Scan(&c.ProviderID, &c.Symbol, &c.Name, &c.Enabled)
+	return c, err
+}
+
+// AddCoin — добавить монету в реестр.
+func (r *CoinRepo) AddCoin(ctx context.Context, coin domain.CoinInfo) error {
+	const query = `
+		INSERT INTO coins (symbol, name, provider_id, enabled)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.Exec(ctx, query, coin.Symbol, coin.Name, coin.ProviderID, coin.Enabled)
+	return err
+}
+
+// UpdateCoin — изменить флаг enabled и/или название монеты; nil-поля не трогаем.
+func (r *CoinRepo) UpdateCoin(ctx context.Context, symbol string, enabled *bool, name *string) (domain.CoinInfo, error) {
+	const query = `
+		UPDATE coins
+		SET enabled = COALESCE($2, enabled),
+		    name    = COALESCE($3, name)
+		WHERE symbol = $1
+		RETURNING provider_id, symbol, name, enabled
+	`
+	var c domain.CoinInfo
+	err := r.db.QueryRow(ctx, query, symbol, enabled, name).Scan(&c.ProviderID, &c.Symbol, &c.Name, &c.Enabled)
+	return c, err
+}
+
+// DeleteCoin — удалить монету из реестра (история цен удаляется каскадно).
+// Если монеты нет — возвращает pgx.ErrNoRows.
+func (r *CoinRepo) DeleteCoin(ctx context.Context, symbol string) error {
+	const query = `DELETE FROM coins WHERE symbol = $1`
+	tag, err := r.db.Exec(ctx, query, symbol)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 )
 
 type CoinRepo struct {
@@ -16,37 +18,77 @@ func NewCoinRepo(db *pgxpool.Pool) *CoinRepo {
 	return &CoinRepo{db: db}
 }
 
-// SaveCoins — сохранить список курсов в таблицу prices.
+// saveBatchSize — строк в одном pgx.Batch; крупные снимки (backfill) уходят несколькими батчами в той же транзакции
+const saveBatchSize = 1000
+
+const (
+	insertPriceQuery = `
+		INSERT INTO prices (coin_symbol, currency, value, source, spread_pct, timestamp,
+		                    volume_24h, market_cap, high_24h, low_24h, change_24h_pct, circulating_supply)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (coin_symbol, currency, timestamp)
+		DO UPDATE SET value = EXCLUDED.value, source = EXCLUDED.source, spread_pct = EXCLUDED.spread_pct,
+		              volume_24h = EXCLUDED.volume_24h, market_cap = EXCLUDED.market_cap,
+		              high_24h = EXCLUDED.high_24h, low_24h = EXCLUDED.low_24h,
+		              change_24h_pct = EXCLUDED.change_24h_pct, circulating_supply = EXCLUDED.circulating_supply
+	`
+	insertQuoteQuery = `
+		INSERT INTO price_quotes (coin_symbol, currency, timestamp, source, value, rejected)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (coin_symbol, currency, timestamp, source)
+		DO UPDATE SET value = EXCLUDED.value, rejected = EXCLUDED.rejected
+	`
+)
+
+// SaveCoins — сохранить список курсов в таблицу prices (и котировки провайдеров в price_quotes).
+// Все строки пишутся батчами в одной транзакции: снимок сохраняется целиком или не сохраняется вовсе.
 func (r *CoinRepo) SaveCoins(ctx context.Context, items []domain.Coin) error {
 	if len(items) == 0 {
 		return nil
 	}
 
-	const query = `
-		INSERT INTO prices (coin_symbol, value, timestamp)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (coin_symbol, timestamp)
-		DO UPDATE SET value = EXCLUDED.value
-	`
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, it := range items {
+			var spread *float64
+			if len(it.Quotes) > 0 {
+				spread = &it.SpreadPct
+			}
+			m := it.Market
+			batch.Queue(insertPriceQuery, it.Symbol, it.Currency, it.Price, it.Source, spread, it.UpdatedAt,
+				m.Volume24h, m.MarketCap, m.High24h, m.Low24h, m.Change24hPct, m.CirculatingSupply)
+			for _, q := range it.Quotes {
+				batch.Queue(insertQuoteQuery, it.Symbol, it.Currency, it.UpdatedAt, q.Source, q.Price, q.Rejected)
+			}
 
-	for _, it := range items {
-		if _, err := r.db.Exec(ctx, query, it.Symbol, it.Price, it.UpdatedAt); err != nil {
-			return err
+			if batch.Len() >= saveBatchSize {
+				if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+					return err
+				}
+				batch = &pgx.Batch{}
+			}
 		}
-	}
-	return nil
+		if batch.Len() == 0 {
+			return nil
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
 }
 
-// GetAllCoins — получить последние цены для всех монет.
-func (r *CoinRepo) GetAllCoins(ctx context.Context) ([]domain.Coin, error) {
+// GetAllCoins — получить последние цены в валюте currency для всех включённых монет реестра.
+func (r *CoinRepo) GetAllCoins(ctx context.Context, currency string) ([]domain.Coin, error) {
 	const query = `
-		SELECT DISTINCT ON (coin_symbol)
-		       coin_symbol, value, timestamp
-		FROM prices
-		ORDER BY coin_symbol, timestamp DESC
+		SELECT DISTINCT ON (p.coin_symbol)
+		       p.coin_symbol, p.currency, p.value, p.source, p.timestamp,
+		       p.volume_24h, p.market_cap, p.high_24h, p.low_24h, p.change_24h_pct, p.circulating_supply
+		FROM prices p
+		JOIN coins c ON c.symbol = p.coin_symbol
+		WHERE c.enabled
+		  AND p.currency = $1
+		ORDER BY p.coin_symbol, p.timestamp DESC
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, currency)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +97,9 @@ func (r *CoinRepo) GetAllCoins(ctx context.Context) ([]domain.Coin, error) {
 	var out []domain.Coin
 	for rows.Next() {
 		var c domain.Coin
-		if err := rows.Scan(&c.Symbol, &c.Price, &c.UpdatedAt); err != nil {
+		if err := rows.Scan(&c.Symbol, &c.Currency, &c.Price, &c.Source, &c.UpdatedAt,
+			&c.Market.Volume24h, &c.Market.MarketCap, &c.Market.High24h, &c.Market.Low24h,
+			&c.Market.Change24hPct, &c.Market.CirculatingSupply); err != nil {
 			return nil, err
 		}
 		out = append(out, c)
@@ -66,41 +110,202 @@ func (r *CoinRepo) GetAllCoins(ctx context.Context) ([]domain.Coin, error) {
 	return out, nil
 }
 
-// GetCoinBySymbol — получить последнюю цену по символу монеты.
-func (r *CoinRepo) GetCoinBySymbol(ctx context.Context, symbol string) (domain.Coin, error) {
+// GetCoinBySymbol — получить последнюю цену по символу монеты в валюте currency.
+func (r *CoinRepo) GetCoinBySymbol(ctx context.Context, symbol, currency string) (domain.Coin, error) {
 	const query = `
-		SELECT coin_symbol, value, timestamp
+		SELECT coin_symbol, currency, value, source, timestamp,
+		       volume_24h, market_cap, high_24h, low_24h, change_24h_pct, circulating_supply
 		FROM prices
 		WHERE coin_symbol = $1
+		  AND currency = $2
 		ORDER BY timestamp DESC
 		LIMIT 1
 	`
 	var c domain.Coin
-	err := r.db.QueryRow(ctx, query, symbol).Scan(&c.Symbol, &c.Price, &c.UpdatedAt)
+	err := r.db.QueryRow(ctx, query, symbol, currency).Scan(&c.Symbol, &c.Currency, &c.Price, &c.Source, &c.UpdatedAt,
+		&c.Market.Volume24h, &c.Market.MarketCap, &c.Market.High24h, &c.Market.Low24h,
+		&c.Market.Change24hPct, &c.Market.CirculatingSupply)
 	return c, err
 }
 
-// History — получить историю цен по монете за указанный период.
-func (r *CoinRepo) History(ctx context.Context, symbol string, from, to time.Time) ([]domain.Coin, error) {
+// Источники цен, свёрнутых задачей хранения (prices_hourly / prices_daily)
+const (
+	SourceHourly = "hourly"
+	SourceDaily  = "daily"
+)
+
+// allPricesQuery — цены монеты $1 в валюте $2 во всех разрешениях: сырые точки и цены закрытия
+// свёрнутых часов и суток (время — последней свёрнутой точки, source — hourly/daily).
+// Периоды не пересекаются: свёртка удаляет исходные строки в той же транзакции.
+const allPricesQuery = `
+	SELECT value, source, timestamp
+	FROM prices
+	WHERE coin_symbol = $1 AND currency = $2
+	UNION ALL
+	SELECT close, '` + SourceHourly + `', closed_at
+	FROM prices_hourly
+	WHERE coin_symbol = $1 AND currency = $2
+	UNION ALL
+	SELECT close, '` + SourceDaily + `', closed_at
+	FROM prices_daily
+	WHERE coin_symbol = $1 AND currency = $2
+`
+
+// PriceRange — минимум и максимум цены монеты в валюте currency за [from, to].
+// Свёрнутые часы и сутки дают свои low/high (а не только цену закрытия) и учитываются целиком,
+// если пересекаются с окном. Нет ни одной цены — pgx.ErrNoRows.
+func (r *CoinRepo) PriceRange(ctx context.Context, symbol, currency string, from, to time.Time) (low, high decimal.Decimal, err error) {
 	const query = `
-		SELECT coin_symbol, value, timestamp
-		FROM prices
-		WHERE coin_symbol = $1
-		  AND timestamp BETWEEN $2 AND $3
-		ORDER BY timestamp
+		SELECT min(low), max(high)
+		FROM (
+			SELECT value AS low, value AS high
+			FROM prices
+			WHERE coin_symbol = $1 AND currency = $2
+			  AND timestamp BETWEEN $3 AND $4
+			UNION ALL
+			SELECT low, high
+			FROM prices_hourly
+			WHERE coin_symbol = $1 AND currency = $2
+			  AND closed_at >= $3 AND opened_at <= $4
+			UNION ALL
+			SELECT low, high
+			FROM prices_daily
+			WHERE coin_symbol = $1 AND currency = $2
+			  AND closed_at >= $3 AND opened_at <= $4
+		) r
 	`
-	rows, err := r.db.Query(ctx, query, symbol, from, to)
+	var lo, hi decimal.NullDecimal
+	if err := r.db.QueryRow(ctx, query, symbol, currency, from, to).Scan(&lo, &hi); err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+	if !lo.Valid || !hi.Valid {
+		return decimal.Decimal{}, decimal.Decimal{}, pgx.ErrNoRows
+	}
+	return lo.Decimal, hi.Decimal, nil
+}
+
+// PricesAt — для каждого момента из at последняя цена не позже него; порядок результата совпадает с at.
+// Если цены нет, элемент — нулевой domain.Coin.
+func (r *CoinRepo) PricesAt(ctx context.Context, symbol, currency string, at []time.Time) ([]domain.Coin, error) {
+	const query = `
+		SELECT t.i, p.value, p.source, p.timestamp
+		FROM unnest($3::timestamptz[]) WITH ORDINALITY AS t(at, i)
+		LEFT JOIN LATERAL (
+			SELECT value, source, timestamp
+			FROM (` + allPricesQuery + `) a
+			WHERE timestamp <= t.at
+			ORDER BY timestamp DESC
+			LIMIT 1
+		) p ON true
+	`
+	rows, err := r.db.Query(ctx, query, symbol, currency, at)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var out []domain.Coin
+	out := make([]domain.Coin, len(at))
 	for rows.Next() {
-		var c domain.Coin
-		if err := rows.Scan(&c.Symbol, &c.Price, &c.UpdatedAt); err != nil {
+		var (
+			i      int
+			value  decimal.NullDecimal
+			source *string
+			ts     *time.Time
+		)
+		if err := rows.Scan(&i, &value, &source, &ts); err != nil {
+			return nil, err
+		}
+		if !value.Valid || i < 1 || i > len(at) {
+			continue
+		}
+		out[i-1] = domain.Coin{Symbol: symbol, Currency: currency, Price: value.Decimal, Source: *source, UpdatedAt: *ts}
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return out, nil
+}
+
+// HistoryPage — страница истории цен за [from, to) после позиции after.
+// Ключ пагинации — (timestamp, source): timestamp уникален только внутри одной таблицы, а сырая цена
+// и свёрнутый агрегат (closed_at) могут прийти с одним временем.
+func (r *CoinRepo) HistoryPage(ctx context.Context, symbol, currency string, from, to time.Time, after domain.HistoryCursor, limit int) ([]domain.Coin, error) {
+	const query = `
+		SELECT value, source, timestamp
+		FROM (` + allPricesQuery + `) p
+		WHERE timestamp >= $3 AND timestamp < $4
+		  AND (timestamp, source) > ($5, $6)
+		ORDER BY timestamp, source
+		LIMIT $7
+	`
+	rows, err := r.db.Query(ctx, query, symbol, currency, from, to, after.Time, after.Source, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.Coin, 0, limit)
+	for rows.Next() {
+		c := domain.Coin{Symbol: symbol, Currency: currency}
+		if err := rows.Scan(&c.Price, &c.Source, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return out, nil
+}
+
+// Candles — OHLC-свечи по монете в валюте currency за период [from, to).
+// Интервалы выровнены date_bin от начала эпохи Unix, пустые интервалы не возвращаются.
+// Свёрнутые периоды учитываются, если агрегат не крупнее свечи: часовые — для свечей от часа, дневные — от суток.
+// Свечи короче часа за свёрнутые периоды не возвращаются: минутных цен там уже нет (отражено в openapi).
+func (r *CoinRepo) Candles(ctx context.Context, symbol, currency string, interval time.Duration, from, to time.Time) ([]domain.Candle, error) {
+	const query = `
+		WITH src AS (
+			SELECT timestamp AS bucket_at, timestamp AS opened_at, timestamp AS closed_at,
+			       value AS open, value AS high, value AS low, value AS close, 1 AS points
+			FROM prices
+			WHERE coin_symbol = $1 AND currency = $2
+			  AND timestamp >= $4 AND timestamp < $5
+			UNION ALL
+			SELECT bucket, opened_at, closed_at, open, high, low, close, points
+			FROM prices_hourly
+			WHERE coin_symbol = $1 AND currency = $2
+			  AND bucket >= $4 AND bucket < $5
+			  AND $3::bigint >= 3600
+			UNION ALL
+			SELECT bucket, opened_at, closed_at, open, high, low, close, points
+			FROM prices_daily
+			WHERE coin_symbol = $1 AND currency = $2
+			  AND bucket >= $4 AND bucket < $5
+			  AND $3::bigint >= 86400
+		)
+		SELECT date_bin($3::bigint * interval '1 second', bucket_at, TIMESTAMPTZ 'epoch') AS bucket,
+		       (array_agg(open ORDER BY opened_at))[1]       AS open,
+		       max(high)                                     AS high,
+		       min(low)                                      AS low,
+		       (array_agg(close ORDER BY closed_at DESC))[1] AS close,
+		       sum(points)                                   AS points
+		FROM src
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+	rows, err := r.db.Query(ctx, query, symbol, currency, int64(interval/time.Second), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Candle
+	for rows.Next() {
+		var c domain.Candle
+		if err := rows.Scan(&c.Time, &c.Open, &c.High, &c.Low, &c.Close, &c.Points); err != nil {
 			return nil, err
 		}
+		c.Time = c.Time.UTC()
 		out = append(out, c)
 	}
 	if rows.Err() != nil {
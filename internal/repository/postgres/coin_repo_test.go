@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Тесты на живой БД с применёнными миграциями (DB_URL, см. coin_repo_bench_test.go); без неё пропускаются.
+
+// seedHourly — часовой агрегат символа symbol за час, начинающийся в bucket
+func seedHourly(t *testing.T, r *CoinRepo, symbol string, bucket time.Time, open, high, low, close string) {
+	t.Helper()
+	_, err := r.db.Exec(context.Background(), `
+		INSERT INTO prices_hourly (coin_symbol, currency, bucket, open, high, low, close, points, opened_at, closed_at)
+		VALUES ($1, 'usd', $2, $3, $4, $5, $6, 60, $2, $7)`,
+		symbol, bucket, open, high, low, close, bucket.Add(59*time.Minute))
+	if err != nil {
+		t.Fatalf("seed hourly: %v", err)
+	}
+}
+
+func TestPriceRange_UsesRollupHighLow(t *testing.T) {
+	r, symbol := dbRepo(t)
+	ctx := context.Background()
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	// свёрнутый час: цена закрытия в середине диапазона, экстремумы — только в high/low
+	seedHourly(t, r, symbol, now.Add(-48*time.Hour), "100", "150", "50", "100")
+	if err := r.SaveCoins(ctx, []domain.Coin{
+		{Symbol: symbol, Currency: "usd", Price: decimal.NewFromInt(90), Source: "test", UpdatedAt: now.Add(-time.Hour)},
+		{Symbol: symbol, Currency: "usd", Price: decimal.NewFromInt(110), Source: "test", UpdatedAt: now},
+	}); err != nil {
+		t.Fatalf("SaveCoins: %v", err)
+	}
+
+	low, high, err := r.PriceRange(ctx, symbol, "usd", now.Add(-7*24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("PriceRange: %v", err)
+	}
+	if !low.Equal(decimal.NewFromInt(50)) || !high.Equal(decimal.NewFromInt(150)) {
+		t.Fatalf("want [50, 150], got [%s, %s]", low, high)
+	}
+
+	// окно без свёрнутого часа — только сырые цены
+	low, high, err = r.PriceRange(ctx, symbol, "usd", now.Add(-2*time.Hour), now)
+	if err != nil {
+		t.Fatalf("PriceRange: %v", err)
+	}
+	if !low.Equal(decimal.NewFromInt(90)) || !high.Equal(decimal.NewFromInt(110)) {
+		t.Fatalf("want [90, 110], got [%s, %s]", low, high)
+	}
+}
+
+func TestHistoryPage_SameTimestampAcrossPageBoundary(t *testing.T) {
+	r, symbol := dbRepo(t)
+	ctx := context.Background()
+
+	bucket := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	closedAt := bucket.Add(59 * time.Minute)
+	// сырая цена с тем же временем, что и closed_at свёрнутого часа (например, догружена backfill)
+	seedHourly(t, r, symbol, bucket, "100", "100", "100", "100")
+	if err := r.SaveCoins(ctx, []domain.Coin{
+		{Symbol: symbol, Currency: "usd", Price: decimal.NewFromInt(101), Source: "coingecko", UpdatedAt: closedAt},
+	}); err != nil {
+		t.Fatalf("SaveCoins: %v", err)
+	}
+
+	from, to := bucket, bucket.Add(2*time.Hour)
+	var (
+		after domain.HistoryCursor
+		got   []string
+	)
+	for i := 0; i < 3; i++ {
+		page, err := r.HistoryPage(ctx, symbol, "usd", from, to, after, 1)
+		if err != nil {
+			t.Fatalf("HistoryPage: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		got = append(got, page[0].Source)
+		after = domain.HistoryCursor{Time: page[0].UpdatedAt, Source: page[0].Source}
+	}
+	if len(got) != 2 || got[0] != "coingecko" || got[1] != SourceHourly {
+		t.Fatalf("want both rows exactly once, got %v", got)
+	}
+}
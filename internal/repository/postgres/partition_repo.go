@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// defaultPartition — секция prices для цен вне месячных секций (миграция 0012)
+const defaultPartition = "prices_default"
+
+// ListPartitions — месячные секции таблицы prices по возрастанию.
+// Границы восстанавливаются по имени секции (prices_YYYY_MM), прочие секции пропускаются.
+func (r *CoinRepo) ListPartitions(ctx context.Context) ([]domain.Partition, error) {
+	const query = `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'prices'::regclass
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Partition
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if p, ok := domain.ParsePartition(name); ok {
+			out = append(out, p)
+		}
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	slices.SortFunc(out, func(a, b domain.Partition) int { return a.From.Compare(b.From) })
+	return out, nil
+}
+
+// CreatePartition — создать месячную секцию. Цены этого периода, уже попавшие в секцию по умолчанию
+// (например, из backfill), переносятся в новую секцию в той же транзакции: иначе ATTACH отклонит её.
+func (r *CoinRepo) CreatePartition(ctx context.Context, p domain.Partition) error {
+	name := pgx.Identifier{p.Name}.Sanitize()
+	// границы в DDL не параметризуются; значения формируются здесь же, а не приходят извне
+	from, to := p.From.UTC().Format(time.RFC3339), p.To.UTC().Format(time.RFC3339)
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(
+			`CREATE TABLE %s (LIKE prices INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`, name)); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`
+			WITH moved AS (
+				DELETE FROM %s WHERE timestamp >= $1 AND timestamp < $2 RETURNING *
+			)
+			INSERT INTO %s SELECT * FROM moved`, pgx.Identifier{defaultPartition}.Sanitize(), name),
+			p.From, p.To); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, fmt.Sprintf(
+			`ALTER TABLE prices ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`, name, from, to))
+		return err
+	})
+}
+
+// DetachPartition — отсоединить секцию от prices; таблица с данными остаётся (например, для выгрузки в архив).
+func (r *CoinRepo) DetachPartition(ctx context.Context, name string) error {
+	_, err := r.db.Exec(ctx, fmt.Sprintf(`ALTER TABLE prices DETACH PARTITION %s`, pgx.Identifier{name}.Sanitize()))
+	return err
+}
+
+// DropPartition — удалить секцию вместе с данными.
+func (r *CoinRepo) DropPartition(ctx context.Context, name string) error {
+	_, err := r.db.Exec(ctx, fmt.Sprintf(`DROP TABLE %s`, pgx.Identifier{name}.Sanitize()))
+	return err
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// rollupConflict — слияние с уже свёрнутым агрегатом: точки, досохранённые в свёрнутый период
+// (например, backfill), добавляются к агрегату, а не перезаписывают его.
+const rollupConflict = `
+	ON CONFLICT (coin_symbol, currency, bucket) DO UPDATE SET
+		open      = CASE WHEN EXCLUDED.opened_at < t.opened_at THEN EXCLUDED.open ELSE t.open END,
+		close     = CASE WHEN EXCLUDED.closed_at > t.closed_at THEN EXCLUDED.close ELSE t.close END,
+		high      = GREATEST(t.high, EXCLUDED.high),
+		low       = LEAST(t.low, EXCLUDED.low),
+		points    = t.points + EXCLUDED.points,
+		opened_at = LEAST(t.opened_at, EXCLUDED.opened_at),
+		closed_at = GREATEST(t.closed_at, EXCLUDED.closed_at)
+`
+
+// RollupRaw — свернуть сырые цены раньше before в часовые агрегаты и удалить их (вместе с котировками провайдеров).
+// before должен быть выровнен по часу, иначе последний час свернётся частично.
+func (r *CoinRepo) RollupRaw(ctx context.Context, before time.Time) (int64, error) {
+	const rollup = `
+		INSERT INTO prices_hourly AS t (coin_symbol, currency, bucket, open, high, low, close, points, opened_at, closed_at)
+		SELECT coin_symbol, currency,
+		       date_bin(interval '1 hour', timestamp, TIMESTAMPTZ 'epoch') AS bucket,
+		       (array_agg(value ORDER BY timestamp))[1],
+		       max(value),
+		       min(value),
+		       (array_agg(value ORDER BY timestamp DESC))[1],
+		       count(*),
+		       min(timestamp),
+		       max(timestamp)
+		FROM prices
+		WHERE timestamp < $1
+		GROUP BY coin_symbol, currency, bucket
+	` + rollupConflict
+	return r.rollup(ctx, before, rollup,
+		`DELETE FROM price_quotes WHERE timestamp < $1`,
+		`DELETE FROM prices WHERE timestamp < $1`)
+}
+
+// RollupHourly — свернуть часовые агрегаты раньше before в дневные и удалить их.
+// before должен быть выровнен по суткам (UTC).
+func (r *CoinRepo) RollupHourly(ctx context.Context, before time.Time) (int64, error) {
+	const rollup = `
+		INSERT INTO prices_daily AS t (coin_symbol, currency, bucket, open, high, low, close, points, opened_at, closed_at)
+		SELECT coin_symbol, currency,
+		       date_bin(interval '1 day', bucket, TIMESTAMPTZ 'epoch') AS day,
+		       (array_agg(open ORDER BY opened_at))[1],
+		       max(high),
+		       min(low),
+		       (array_agg(close ORDER BY closed_at DESC))[1],
+		       sum(points),
+		       min(opened_at),
+		       max(closed_at)
+		FROM prices_hourly
+		WHERE bucket < $1
+		GROUP BY coin_symbol, currency, day
+	` + rollupConflict
+	return r.rollup(ctx, before, rollup,
+		`DELETE FROM prices_hourly WHERE bucket < $1`)
+}
+
+// DeleteDaily — удалить дневные агрегаты раньше before.
+func (r *CoinRepo) DeleteDaily(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM prices_daily WHERE bucket < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// rollup — свёртка и удаление исходных строк в одной транзакции; возвращает число строк, удалённых последним запросом.
+func (r *CoinRepo) rollup(ctx context.Context, before time.Time, rollup string, deletes ...string) (int64, error) {
+	var deleted int64
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, rollup, before); err != nil {
+			return err
+		}
+		for _, q := range deletes {
+			tag, err := tx.Exec(ctx, q, before)
+			if err != nil {
+				return err
+			}
+			deleted = tag.RowsAffected()
+		}
+		return nil
+	})
+	return deleted, err
+}
@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -15,17 +18,37 @@ func NewSubscriptionRepo(db *pgxpool.Pool) *SubscriptionRepo {
 	return &SubscriptionRepo{db: db}
 }
 
-// MarkEnabled включает/обновляет подписку для заданного chatID с указанным интервалом (в минутах).
-// last_sent_at не трогаем — если была подписка ранее, оставляем как есть.
-func (r *SubscriptionRepo) MarkEnabled(ctx context.Context, chatID int64, intervalMinutes int) error {
+// subscriptionColumns — колонки для scanSubscription (таблица s, часовой пояс из chat_settings cs)
+const subscriptionColumns = `
+	s.chat_id, s.schedule_kind, s.interval_minutes, s.schedule_spec, s.coins,
+	COALESCE(cs.timezone, 'UTC'), s.quiet_start, s.quiet_end, s.quiet_summary,
+	s.next_run_at, s.missed_since`
+
+// MarkEnabled включает/обновляет подписку для чата: расписание, набор монет (пустой — все)
+// и время первой рассылки sub.NextRun. Тихие часы подписки сохраняются.
+func (r *SubscriptionRepo) MarkEnabled(ctx context.Context, sub domain.Subscription) error {
 	query := `
-	INSERT INTO subscriptions (chat_id, interval_minutes, enabled, last_sent_at)
-	VALUES ($1, $2, TRUE, NULL)
+	INSERT INTO subscriptions (chat_id, schedule_kind, interval_minutes, schedule_spec, enabled, last_sent_at, next_run_at, coins)
+	VALUES ($1, $2, $3, $4, TRUE, NULL, $5, $6)
 	ON CONFLICT (chat_id)
-	DO UPDATE SET interval_minutes = EXCLUDED.interval_minutes,
+	DO UPDATE SET schedule_kind = EXCLUDED.schedule_kind,
+	              interval_minutes = EXCLUDED.interval_minutes,
+	              schedule_spec = EXCLUDED.schedule_spec,
 	              enabled = TRUE,
-	              last_sent_at = NULL`
-	_, err := r.db.Exec(ctx, query, chatID, intervalMinutes)
+	              last_sent_at = NULL,
+	              next_run_at = EXCLUDED.next_run_at,
+	              missed_since = NULL,
+	              coins = EXCLUDED.coins`
+	coins := sub.Coins
+	if coins == nil {
+		coins = []string{}
+	}
+	var interval *int
+	if sub.Schedule.Kind == domain.ScheduleInterval {
+		mins := int(sub.Schedule.Interval / time.Minute)
+		interval = &mins
+	}
+	_, err := r.db.Exec(ctx, query, sub.ChatID, string(sub.Schedule.Kind), interval, sub.Schedule.Spec, sub.NextRun, coins)
 	return err
 }
 
@@ -36,36 +59,147 @@ func (r *SubscriptionRepo) MarkDisabled(ctx context.Context, chatID int64) error
 	return err
 }
 
-// FindDue возвращает chat_id, для которых наступило время отправки на момент now.
-func (r *SubscriptionRepo) FindDue(ctx context.Context, now time.Time) ([]int64, error) {
-	query := `
-	SELECT chat_id
-	FROM subscriptions
-	WHERE enabled = TRUE
-	  AND (
-		last_sent_at IS NULL
-		OR EXTRACT(EPOCH FROM ($1::timestamptz - last_sent_at)) / 60 >= interval_minutes
-	)`
+// FindDue возвращает подписки, для которых наступило время отправки на момент now.
+func (r *SubscriptionRepo) FindDue(ctx context.Context, now time.Time) ([]domain.Subscription, error) {
+	query := `SELECT` + subscriptionColumns + `
+	FROM subscriptions s
+	LEFT JOIN chat_settings cs ON cs.chat_id = s.chat_id
+	WHERE s.enabled = TRUE AND s.next_run_at <= $1
+	ORDER BY s.next_run_at`
 	rows, err := r.db.Query(ctx, query, now)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var result []int64
+	var result []domain.Subscription
 	for rows.Next() {
-		var id int64
-		if err := rows.Scan(&id); err != nil {
+		sub, err := scanSubscription(rows)
+		if err != nil {
 			return nil, err
 		}
-		result = append(result, id)
+		result = append(result, sub)
 	}
 	return result, rows.Err()
 }
 
-// MarkSent отмечает факт отправки для chatID.
-func (r *SubscriptionRepo) MarkSent(ctx context.Context, chatID int64, at time.Time) error {
-	query := `UPDATE subscriptions SET last_sent_at = $2 WHERE chat_id = $1`
-	_, err := r.db.Exec(ctx, query, chatID, at)
+// GetSubscription — включённая подписка чата. Если её нет — возвращает pgx.ErrNoRows.
+func (r *SubscriptionRepo) GetSubscription(ctx context.Context, chatID int64) (domain.Subscription, error) {
+	query := `SELECT` + subscriptionColumns + `
+	FROM subscriptions s
+	LEFT JOIN chat_settings cs ON cs.chat_id = s.chat_id
+	WHERE s.chat_id = $1 AND s.enabled = TRUE`
+	return scanSubscription(r.db.QueryRow(ctx, query, chatID))
+}
+
+// MarkSent отмечает факт отправки для chatID, сбрасывает пропуски тихих часов и планирует следующую рассылку.
+func (r *SubscriptionRepo) MarkSent(ctx context.Context, chatID int64, at, next time.Time) error {
+	query := `UPDATE subscriptions SET last_sent_at = $2, next_run_at = $3, missed_since = NULL WHERE chat_id = $1`
+	_, err := r.db.Exec(ctx, query, chatID, at, next)
+	return err
+}
+
+// MarkMissed отмечает рассылку, пропущенную в тихие часы в at (запоминается первая), и планирует следующую.
+func (r *SubscriptionRepo) MarkMissed(ctx context.Context, chatID int64, at, next time.Time) error {
+	query := `UPDATE subscriptions SET missed_since = COALESCE(missed_since, $2), next_run_at = $3 WHERE chat_id = $1`
+	_, err := r.db.Exec(ctx, query, chatID, at, next)
+	return err
+}
+
+// Reschedule переносит следующую рассылку chatID на next.
+func (r *SubscriptionRepo) Reschedule(ctx context.Context, chatID int64, next time.Time) error {
+	query := `UPDATE subscriptions SET next_run_at = $2 WHERE chat_id = $1`
+	_, err := r.db.Exec(ctx, query, chatID, next)
+	return err
+}
+
+// Timezone возвращает часовой пояс чата; "UTC", если он не задан.
+func (r *SubscriptionRepo) Timezone(ctx context.Context, chatID int64) (string, error) {
+	var tz string
+	err := r.db.QueryRow(ctx, `SELECT timezone FROM chat_settings WHERE chat_id = $1`, chatID).Scan(&tz)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "UTC", nil
+	}
+	return tz, err
+}
+
+// SetTimezone сохраняет часовой пояс чата.
+func (r *SubscriptionRepo) SetTimezone(ctx context.Context, chatID int64, tz string) error {
+	query := `
+	INSERT INTO chat_settings (chat_id, timezone) VALUES ($1, $2)
+	ON CONFLICT (chat_id) DO UPDATE SET timezone = EXCLUDED.timezone`
+	_, err := r.db.Exec(ctx, query, chatID, tz)
 	return err
 }
+
+// QuietHours возвращает тихие часы подписки чата; нулевое значение, если они не заданы или подписки нет.
+func (r *SubscriptionRepo) QuietHours(ctx context.Context, chatID int64) (domain.QuietHours, error) {
+	var (
+		start, end *int16
+		summary    bool
+	)
+	query := `SELECT quiet_start, quiet_end, quiet_summary FROM subscriptions WHERE chat_id = $1`
+	err := r.db.QueryRow(ctx, query, chatID).Scan(&start, &end, &summary)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.QuietHours{}, nil
+	}
+	if err != nil {
+		return domain.QuietHours{}, err
+	}
+	return quietHours(start, end, summary), nil
+}
+
+// SetQuietHours сохраняет тихие часы подписки чата; нулевое значение — выключить.
+// Если подписки нет (в том числе выключенной) — возвращает pgx.ErrNoRows.
+func (r *SubscriptionRepo) SetQuietHours(ctx context.Context, chatID int64, q domain.QuietHours) error {
+	query := `UPDATE subscriptions SET quiet_start = $2, quiet_end = $3, quiet_summary = $4 WHERE chat_id = $1`
+	var start, end *int16
+	if q.Enabled() {
+		s, e := int16(q.Start/time.Minute), int16(q.End/time.Minute)
+		start, end = &s, &e
+	}
+	tag, err := r.db.Exec(ctx, query, chatID, start, end, q.Summary)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// quietHours — тихие часы из колонок subscriptions (минуты от полуночи)
+func quietHours(start, end *int16, summary bool) domain.QuietHours {
+	if start == nil || end == nil {
+		return domain.QuietHours{}
+	}
+	return domain.QuietHours{
+		Start:   time.Duration(*start) * time.Minute,
+		End:     time.Duration(*end) * time.Minute,
+		Summary: summary,
+	}
+}
+
+func scanSubscription(row pgx.Row) (domain.Subscription, error) {
+	var (
+		sub         domain.Subscription
+		kind        string
+		interval    *int
+		start, end  *int16
+		summary     bool
+		missedSince *time.Time
+	)
+	if err := row.Scan(&sub.ChatID, &kind, &interval, &sub.Schedule.Spec, &sub.Coins,
+		&sub.Timezone, &start, &end, &summary, &sub.NextRun, &missedSince); err != nil {
+		return domain.Subscription{}, err
+	}
+	sub.Quiet = quietHours(start, end, summary)
+	if missedSince != nil {
+		sub.MissedSince = *missedSince
+	}
+	sub.Schedule.Kind = domain.ScheduleKind(kind)
+	if interval != nil {
+		sub.Schedule.Interval = time.Duration(*interval) * time.Minute
+	}
+	return sub, nil
+}
@@ -2,9 +2,11 @@ package scheduler_fetcher
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
 )
 
@@ -49,7 +51,11 @@ func (s *Scheduler) Start(ctx context.Context) {
 func (s *Scheduler) runOnce(ctx context.Context) {
 	s.logger.Debug("tick: running fetch cycle")
 	if err := s.ingestion.FetchAndSaveCurrency(ctx); err != nil {
-		s.logger.Error("tick: fetch failed", slog.Any("err", err))
+		if errors.Is(err, errs.ErrRateLimited) {
+			s.logger.Warn("tick: fetch skipped, provider rate limit exhausted", slog.Any("err", err))
+		} else {
+			s.logger.Error("tick: fetch failed", slog.Any("err", err))
+		}
 	} else {
 		s.logger.Debug("tick: fetch cycle completed")
 	}
@@ -0,0 +1,65 @@
+package scheduler_partitions
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+)
+
+type Scheduler struct {
+	maintainer interfaces.PartitionMaintainer
+	interval   time.Duration
+	logger     *slog.Logger
+}
+
+// NewScheduler — конструктор планировщика обслуживания секций prices
+func NewScheduler(maintainer interfaces.PartitionMaintainer, interval time.Duration, logger *slog.Logger) *Scheduler {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	return &Scheduler{
+		maintainer: maintainer,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Start — запускает периодическое выполнение задачи до остановки контекста
+func (s *Scheduler) Start(ctx context.Context) {
+	s.logger.Info("partitions scheduler started", slog.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// первый запуск сразу: секция текущего месяца нужна до первой записи цен
+	s.runOnce(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce(ctx)
+		case <-ctx.Done():
+			s.logger.Info("partitions scheduler stopped")
+			return
+		}
+	}
+}
+
+// runOnce — одна итерация: создать недостающие секции и убрать истёкшие
+func (s *Scheduler) runOnce(ctx context.Context) {
+	s.logger.Debug("tick: maintaining partitions")
+	started := time.Now()
+	report, err := s.maintainer.MaintainPartitions(ctx)
+	if err != nil {
+		s.logger.Error("tick: partition maintenance failed", slog.Any("err", err))
+		return
+	}
+	s.logger.Debug("tick: partitions maintained",
+		slog.Int("created", len(report.Created)),
+		slog.Int("detached", len(report.Detached)),
+		slog.Int("dropped", len(report.Dropped)),
+		slog.Duration("duration", time.Since(started)),
+	)
+}
@@ -0,0 +1,59 @@
+package scheduler_retention
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+)
+
+type Scheduler struct {
+	retention interfaces.Retention
+	interval  time.Duration
+	logger    *slog.Logger
+}
+
+// NewScheduler — конструктор планировщика свёртки и удаления старых цен
+func NewScheduler(retention interfaces.Retention, interval time.Duration, logger *slog.Logger) *Scheduler {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Scheduler{
+		retention: retention,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Start — запускает периодическое выполнение задачи до остановки контекста
+func (s *Scheduler) Start(ctx context.Context) {
+	s.logger.Info("retention scheduler started", slog.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// первый запуск сразу
+	s.runOnce(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce(ctx)
+		case <-ctx.Done():
+			s.logger.Info("retention scheduler stopped")
+			return
+		}
+	}
+}
+
+// runOnce — одна итерация: свернуть и удалить данные старше сроков хранения
+func (s *Scheduler) runOnce(ctx context.Context) {
+	s.logger.Debug("tick: running retention cycle")
+	started := time.Now()
+	if err := s.retention.ApplyRetention(ctx); err != nil {
+		s.logger.Error("tick: retention failed", slog.Any("err", err))
+		return
+	}
+	s.logger.Debug("tick: retention completed", slog.Duration("duration", time.Since(started)))
+}
@@ -0,0 +1,278 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/botfmt"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// DefaultMaxPerChat — лимит алертов на чат, если не задан
+	DefaultMaxPerChat = 20
+	// MinMoveWindow, MaxMoveWindow — допустимое окно алерта на изменение цены
+	MinMoveWindow = time.Minute
+	MaxMoveWindow = 30 * 24 * time.Hour
+)
+
+// Options — параметры проверки алертов
+type Options struct {
+	Hysteresis decimal.Decimal // доля уровня для повторного взвода (0.005 = 0.5%)
+	MaxPerChat int             // 0 — DefaultMaxPerChat
+}
+
+type Service struct {
+	storage  interfaces.AlertStorage
+	rates    interfaces.Service
+	history  interfaces.PriceHistory
+	notifier interfaces.Notifier
+	opts     Options
+	logger   *slog.Logger
+}
+
+func NewService(storage interfaces.AlertStorage, rates interfaces.Service, history interfaces.PriceHistory, notifier interfaces.Notifier, opts Options, logger *slog.Logger) *Service {
+	if opts.MaxPerChat <= 0 {
+		opts.MaxPerChat = DefaultMaxPerChat
+	}
+	return &Service{
+		storage:  storage,
+		rates:    rates,
+		history:  history,
+		notifier: notifier,
+		opts:     opts,
+		logger:   logger,
+	}
+}
+
+// CreateAlert — алерт на пересечение уровня level. Пустая currency — валюта по умолчанию.
+// Если цена уже за уровнем, алерт создаётся невзведённым: уведомление придёт после следующего пересечения.
+func (s *Service) CreateAlert(ctx context.Context, chatID int64, symbol, currency string, direction domain.AlertDirection, level decimal.Decimal) (domain.Alert, error) {
+	if direction != domain.AlertAbove && direction != domain.AlertBelow {
+		return domain.Alert{}, fmt.Errorf("%w: unknown direction %q", errs.ErrInvalidArgument, direction)
+	}
+	if !level.IsPositive() {
+		return domain.Alert{}, fmt.Errorf("%w: level must be positive", errs.ErrInvalidArgument)
+	}
+	a, err := s.newAlert(ctx, chatID, symbol, currency)
+	if err != nil {
+		return domain.Alert{}, err
+	}
+	a.Kind, a.Direction, a.Level = domain.AlertLevel, direction, level
+
+	// нет сохранённой цены — алерт взведён и сработает на первой же цене за уровнем
+	latest, err := s.rates.GetLatest(ctx, a.Currency)
+	if err != nil && !errors.Is(err, errs.ErrPriceNotFound) {
+		return domain.Alert{}, err
+	}
+	if i := slices.IndexFunc(latest, func(c domain.Coin) bool { return c.Symbol == a.Symbol }); i >= 0 {
+		a.Armed = !a.Crossed(latest[i].Price)
+	}
+	return s.save(ctx, a)
+}
+
+// CreateMoveAlert — алерт на изменение цены за window не меньше pct процентов
+// (direction: above — рост, below — падение, any — в любую сторону).
+// После срабатывания алерт молчит cooldown; 0 — пауза равна окну.
+func (s *Service) CreateMoveAlert(ctx context.Context, chatID int64, symbol, currency string, direction domain.AlertDirection, pct decimal.Decimal, window, cooldown time.Duration) (domain.Alert, error) {
+	if direction != domain.AlertAbove && direction != domain.AlertBelow && direction != domain.AlertAny {
+		return domain.Alert{}, fmt.Errorf("%w: unknown direction %q", errs.ErrInvalidArgument, direction)
+	}
+	if !pct.IsPositive() {
+		return domain.Alert{}, fmt.Errorf("%w: percent must be positive", errs.ErrInvalidArgument)
+	}
+	if window < MinMoveWindow || window > MaxMoveWindow {
+		return domain.Alert{}, fmt.Errorf("%w: window must be between %s and %s",
+			errs.ErrInvalidArgument, utils.FormatWindow(MinMoveWindow), utils.FormatWindow(MaxMoveWindow))
+	}
+	if cooldown < 0 {
+		return domain.Alert{}, fmt.Errorf("%w: cooldown must not be negative", errs.ErrInvalidArgument)
+	}
+	if cooldown == 0 {
+		cooldown = window
+	}
+	a, err := s.newAlert(ctx, chatID, symbol, currency)
+	if err != nil {
+		return domain.Alert{}, err
+	}
+	a.Kind, a.Direction, a.Level = domain.AlertMove, direction, pct
+	a.Window, a.Cooldown = window.Truncate(time.Second), cooldown.Truncate(time.Second)
+	return s.save(ctx, a)
+}
+
+// newAlert — взведённый алерт чата после проверки монеты, валюты и лимита алертов
+func (s *Service) newAlert(ctx context.Context, chatID int64, symbol, currency string) (domain.Alert, error) {
+	a := domain.Alert{
+		ChatID:   chatID,
+		Symbol:   strings.ToUpper(strings.TrimSpace(symbol)),
+		Currency: strings.ToLower(strings.TrimSpace(currency)),
+		Armed:    true,
+	}
+	if a.Currency == "" {
+		a.Currency = s.rates.Currencies()[0]
+	} else if !slices.Contains(s.rates.Currencies(), a.Currency) {
+		return domain.Alert{}, fmt.Errorf("%w: %s", errs.ErrUnsupportedCurrency, a.Currency)
+	}
+
+	tracked, err := s.rates.TrackedCoins(ctx)
+	if err != nil {
+		return domain.Alert{}, err
+	}
+	if !slices.ContainsFunc(tracked, func(c domain.CoinInfo) bool { return c.Symbol == a.Symbol }) {
+		return domain.Alert{}, fmt.Errorf("%w: %s", errs.ErrCoinNotFound, a.Symbol)
+	}
+
+	existing, err := s.storage.ListAlerts(ctx, chatID)
+	if err != nil {
+		s.logger.Error("failed to list alerts", "chat_id", chatID, "err", err)
+		return domain.Alert{}, fmt.Errorf("%w: storage.ListAlerts(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	if len(existing) >= s.opts.MaxPerChat {
+		return domain.Alert{}, fmt.Errorf("%w: limit is %d", errs.ErrTooManyAlerts, s.opts.MaxPerChat)
+	}
+	return a, nil
+}
+
+func (s *Service) save(ctx context.Context, a domain.Alert) (domain.Alert, error) {
+	var err error
+	if a.ID, err = s.storage.CreateAlert(ctx, a); err != nil {
+		s.logger.Error("failed to create alert", "chat_id", a.ChatID, "symbol", a.Symbol, "err", err)
+		return domain.Alert{}, fmt.Errorf("%w: storage.CreateAlert(%s): %w", errs.ErrInternal, a.Symbol, err)
+	}
+	s.logger.Info("alert created", "id", a.ID, "chat_id", a.ChatID, "kind", a.Kind, "symbol", a.Symbol,
+		"direction", a.Direction, "level", a.Level.String(), "armed", a.Armed)
+	return a, nil
+}
+
+// ListAlerts — алерты чата.
+func (s *Service) ListAlerts(ctx context.Context, chatID int64) ([]domain.Alert, error) {
+	items, err := s.storage.ListAlerts(ctx, chatID)
+	if err != nil {
+		s.logger.Error("failed to list alerts", "chat_id", chatID, "err", err)
+		return nil, fmt.Errorf("%w: storage.ListAlerts(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	return items, nil
+}
+
+// DeleteAlert — удалить алерт чата; чужой или несуществующий id — ErrAlertNotFound.
+func (s *Service) DeleteAlert(ctx context.Context, chatID, id int64) error {
+	if err := s.storage.DeleteAlert(ctx, chatID, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errs.ErrAlertNotFound
+		}
+		s.logger.Error("failed to delete alert", "chat_id", chatID, "id", id, "err", err)
+		return fmt.Errorf("%w: storage.DeleteAlert(%d): %w", errs.ErrInternal, id, err)
+	}
+	s.logger.Info("alert deleted", "chat_id", chatID, "id", id)
+	return nil
+}
+
+// OnPrices — проверка алертов по ценам очередного цикла загрузки.
+// level: уведомление один раз на пересечение; если отправка не удалась, алерт остаётся взведённым
+// и сработает на следующей цене за уровнем.
+// move: изменение считается от сохранённой цены на момент за окно до новой цены; после срабатывания — пауза.
+func (s *Service) OnPrices(ctx context.Context, prices []domain.Coin) {
+	latest := make(map[string]domain.Coin, len(prices))
+	var symbols []string
+	for _, p := range prices {
+		latest[priceKey(p.Symbol, p.Currency)] = p
+		if !slices.Contains(symbols, p.Symbol) {
+			symbols = append(symbols, p.Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return
+	}
+
+	alerts, err := s.storage.AlertsBySymbols(ctx, symbols)
+	if err != nil {
+		s.logger.Error("failed to load alerts", "err", err)
+		return
+	}
+
+	now := utils.NowFunc()
+	fired := 0
+	for _, a := range alerts {
+		price, ok := latest[priceKey(a.Symbol, a.Currency)]
+		if !ok {
+			continue
+		}
+		var fire bool
+		switch a.Kind {
+		case domain.AlertMove:
+			fire = s.checkMove(ctx, a, price, now)
+		default:
+			fire = s.checkLevel(ctx, a, price)
+		}
+		if fire {
+			fired++
+		}
+	}
+	if fired > 0 {
+		s.logger.Info("alerts fired", "count", fired)
+	}
+}
+
+// checkLevel — пересечение уровня с гистерезисом
+func (s *Service) checkLevel(ctx context.Context, a domain.Alert, price domain.Coin) bool {
+	fire, armed := a.Evaluate(price.Price, s.opts.Hysteresis)
+	switch {
+	case fire:
+		return s.fire(ctx, a, botfmt.FormatAlertTriggered(a, price), armed)
+	case armed != a.Armed:
+		if err := s.storage.UpdateAlertState(ctx, a.ID, armed, time.Time{}); err != nil {
+			s.logger.Error("failed to save alert state", "id", a.ID, "err", err)
+			return false
+		}
+		s.logger.Debug("alert re-armed", "id", a.ID, "symbol", a.Symbol)
+	}
+	return false
+}
+
+// checkMove — изменение цены за окно алерта относительно сохранённой истории
+func (s *Service) checkMove(ctx context.Context, a domain.Alert, price domain.Coin, now time.Time) bool {
+	if a.CoolingDown(now) {
+		return false
+	}
+	base, err := s.history.PricesAt(ctx, a.Symbol, a.Currency, []time.Time{price.UpdatedAt.Add(-a.Window)})
+	if err != nil {
+		s.logger.Error("failed to load base price", "id", a.ID, "symbol", a.Symbol, "err", err)
+		return false
+	}
+	// истории на начало окна ещё нет — изменение не считаем
+	if len(base) == 0 || !base[0].Price.IsPositive() {
+		return false
+	}
+	pct := price.Price.Sub(base[0].Price).Div(base[0].Price).Mul(decimal.NewFromInt(100))
+	if !a.MoveFired(pct) {
+		return false
+	}
+	return s.fire(ctx, a, botfmt.FormatMoveAlertTriggered(a, price, pct), a.Armed)
+}
+
+// fire — отправить уведомление и записать срабатывание; при ошибке отправки состояние не меняется
+func (s *Service) fire(ctx context.Context, a domain.Alert, text string, armed bool) bool {
+	if err := s.notifier.Notify(ctx, a.ChatID, text); err != nil {
+		s.logger.Error("alert notify failed", "id", a.ID, "chat_id", a.ChatID, "err", err)
+		return false
+	}
+	if err := s.storage.UpdateAlertState(ctx, a.ID, armed, utils.NowFunc()); err != nil {
+		s.logger.Error("failed to save alert state", "id", a.ID, "err", err)
+		return false
+	}
+	return true
+}
+
+func priceKey(symbol, currency string) string {
+	return symbol + "/" + currency
+}
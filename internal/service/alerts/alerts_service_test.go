@@ -0,0 +1,273 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	alertsmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/alerts/mocks"
+	ratesmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/rates/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+var now = time.Date(2025, 9, 16, 12, 0, 0, 0, time.UTC)
+
+type deps struct {
+	storage  *alertsmocks.MockAlertStorage
+	history  *alertsmocks.MockPriceHistory
+	notifier *alertsmocks.MockNotifier
+	rates    *ratesmocks.MockService
+}
+
+// helper to build service with mocks
+func setupSvc(t *testing.T) (context.Context, *gomock.Controller, deps, *Service) {
+	t.Helper()
+	prev := utils.NowFunc
+	utils.NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { utils.NowFunc = prev })
+
+	ctrl := gomock.NewController(t)
+	d := deps{
+		storage:  alertsmocks.NewMockAlertStorage(ctrl),
+		history:  alertsmocks.NewMockPriceHistory(ctrl),
+		notifier: alertsmocks.NewMockNotifier(ctrl),
+		rates:    ratesmocks.NewMockService(ctrl),
+	}
+	d.rates.EXPECT().Currencies().Return([]string{"usd", "eur"}).AnyTimes()
+	svc := NewService(d.storage, d.rates, d.history, d.notifier, Options{Hysteresis: decimal.RequireFromString("0.01")}, slog.Default())
+	return context.Background(), ctrl, d, svc
+}
+
+func dec(v int64) decimal.Decimal { return decimal.NewFromInt(v) }
+
+// btcAbove — сохранённый алерт чата 42 (id 7)
+func btcAbove(level int64, armed bool) domain.Alert {
+	return domain.Alert{ID: 7, ChatID: 42, Kind: domain.AlertLevel, Symbol: "BTC", Currency: "usd", Direction: domain.AlertAbove, Level: dec(level), Armed: armed}
+}
+
+func btcPrice(v int64) []domain.Coin {
+	return []domain.Coin{{Symbol: "BTC", Currency: "usd", Price: dec(v), UpdatedAt: now}}
+}
+
+func TestCreateAlert_ArmedBelowLevel(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	d.rates.EXPECT().TrackedCoins(gomock.Any()).Return([]domain.CoinInfo{{Symbol: "BTC"}}, nil)
+	d.storage.EXPECT().ListAlerts(gomock.Any(), int64(42)).Return(nil, nil)
+	d.rates.EXPECT().GetLatest(gomock.Any(), "usd").Return(btcPrice(65000), nil)
+	want := btcAbove(70000, true)
+	want.ID = 0
+	d.storage.EXPECT().CreateAlert(gomock.Any(), want).Return(int64(7), nil)
+
+	got, err := svc.CreateAlert(ctx, 42, "btc", "", domain.AlertAbove, dec(70000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != 7 || !got.Armed {
+		t.Fatalf("unexpected alert: %+v", got)
+	}
+}
+
+func TestCreateAlert_AlreadyCrossed(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	// цена уже выше уровня — ждём следующего пересечения, а не уведомляем сразу
+	d.rates.EXPECT().TrackedCoins(gomock.Any()).Return([]domain.CoinInfo{{Symbol: "BTC"}}, nil)
+	d.storage.EXPECT().ListAlerts(gomock.Any(), int64(42)).Return(nil, nil)
+	d.rates.EXPECT().GetLatest(gomock.Any(), "usd").Return(btcPrice(75000), nil)
+	want := btcAbove(70000, false)
+	want.ID = 0
+	d.storage.EXPECT().CreateAlert(gomock.Any(), want).Return(int64(7), nil)
+
+	if _, err := svc.CreateAlert(ctx, 42, "BTC", "usd", domain.AlertAbove, dec(70000)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateAlert_Invalid(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	if _, err := svc.CreateAlert(ctx, 42, "BTC", "", domain.AlertAbove, dec(0)); !errors.Is(err, derrors.ErrInvalidArgument) {
+		t.Errorf("zero level: expected ErrInvalidArgument, got %v", err)
+	}
+	if _, err := svc.CreateAlert(ctx, 42, "BTC", "jpy", domain.AlertAbove, dec(1)); !errors.Is(err, derrors.ErrUnsupportedCurrency) {
+		t.Errorf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+
+	d.rates.EXPECT().TrackedCoins(gomock.Any()).Return([]domain.CoinInfo{{Symbol: "BTC"}}, nil).Times(2)
+	if _, err := svc.CreateAlert(ctx, 42, "DOGE", "", domain.AlertAbove, dec(1)); !errors.Is(err, derrors.ErrCoinNotFound) {
+		t.Errorf("expected ErrCoinNotFound, got %v", err)
+	}
+
+	d.storage.EXPECT().ListAlerts(gomock.Any(), int64(42)).Return(make([]domain.Alert, DefaultMaxPerChat), nil)
+	if _, err := svc.CreateAlert(ctx, 42, "BTC", "", domain.AlertAbove, dec(1)); !errors.Is(err, derrors.ErrTooManyAlerts) {
+		t.Errorf("expected ErrTooManyAlerts, got %v", err)
+	}
+}
+
+func TestDeleteAlert_NotFound(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	d.storage.EXPECT().DeleteAlert(gomock.Any(), int64(42), int64(7)).Return(pgx.ErrNoRows)
+
+	if err := svc.DeleteAlert(ctx, 42, 7); !errors.Is(err, derrors.ErrAlertNotFound) {
+		t.Fatalf("expected ErrAlertNotFound, got %v", err)
+	}
+}
+
+func TestOnPrices_FiresOncePerCrossing(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcAbove(70000, true)}, nil),
+		d.notifier.EXPECT().Notify(gomock.Any(), int64(42), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ int64, text string) error {
+				if !strings.Contains(text, "BTC поднялся выше 70000.00 USD") {
+					t.Errorf("unexpected notification: %s", text)
+				}
+				return nil
+			}),
+		d.storage.EXPECT().UpdateAlertState(gomock.Any(), int64(7), false, now).Return(nil),
+	)
+	svc.OnPrices(ctx, btcPrice(70100))
+
+	// цена осталась за уровнем — повторного уведомления нет
+	d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcAbove(70000, false)}, nil)
+	svc.OnPrices(ctx, btcPrice(71000))
+}
+
+func TestOnPrices_Hysteresis(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	// возврат под уровень в пределах 1% не взводит алерт
+	d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcAbove(70000, false)}, nil)
+	svc.OnPrices(ctx, btcPrice(69500))
+
+	// ниже 69300 — взводится без уведомления
+	d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcAbove(70000, false)}, nil)
+	d.storage.EXPECT().UpdateAlertState(gomock.Any(), int64(7), true, time.Time{}).Return(nil)
+	svc.OnPrices(ctx, btcPrice(69200))
+}
+
+func TestOnPrices_NotifyFailureKeepsArmed(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	// другая валюта котировки не проверяется; при ошибке отправки состояние не меняется
+	eur := btcAbove(100, true)
+	eur.ID, eur.Currency = 8, "eur"
+	d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcAbove(70000, true), eur}, nil)
+	d.notifier.EXPECT().Notify(gomock.Any(), int64(42), gomock.Any()).Return(errors.New("telegram down"))
+
+	svc.OnPrices(ctx, btcPrice(70100))
+}
+
+// btcMove — сохранённый алерт «BTC ±5% за 1ч» с паузой 2ч
+func btcMove(direction domain.AlertDirection) domain.Alert {
+	return domain.Alert{ID: 9, ChatID: 42, Kind: domain.AlertMove, Symbol: "BTC", Currency: "usd", Direction: direction,
+		Level: dec(5), Window: time.Hour, Cooldown: 2 * time.Hour, Armed: true}
+}
+
+func TestCreateMoveAlert_DefaultCooldown(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	want := btcMove(domain.AlertAny)
+	want.ID, want.Cooldown = 0, time.Hour
+	d.rates.EXPECT().TrackedCoins(gomock.Any()).Return([]domain.CoinInfo{{Symbol: "BTC"}}, nil)
+	d.storage.EXPECT().ListAlerts(gomock.Any(), int64(42)).Return(nil, nil)
+	d.storage.EXPECT().CreateAlert(gomock.Any(), want).Return(int64(9), nil)
+
+	// пауза по умолчанию — длина окна
+	if _, err := svc.CreateMoveAlert(ctx, 42, "BTC", "", domain.AlertAny, dec(5), time.Hour, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateMoveAlert_Invalid(t *testing.T) {
+	ctx, ctrl, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name     string
+		pct      decimal.Decimal
+		window   time.Duration
+		cooldown time.Duration
+	}{
+		{"zero percent", dec(0), time.Hour, 0},
+		{"window too short", dec(5), time.Second, 0},
+		{"window too long", dec(5), MaxMoveWindow + time.Hour, 0},
+		{"negative cooldown", dec(5), time.Hour, -time.Minute},
+	}
+	for _, tc := range cases {
+		if _, err := svc.CreateMoveAlert(ctx, 42, "BTC", "", domain.AlertAny, tc.pct, tc.window, tc.cooldown); !errors.Is(err, derrors.ErrInvalidArgument) {
+			t.Errorf("%s: expected ErrInvalidArgument, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestOnPrices_MoveFires(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	// 60000 → 63300 за час: +5.5%
+	gomock.InOrder(
+		d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcMove(domain.AlertAny)}, nil),
+		d.history.EXPECT().PricesAt(gomock.Any(), "BTC", "usd", []time.Time{now.Add(-time.Hour)}).
+			Return([]domain.Coin{{Symbol: "BTC", Currency: "usd", Price: dec(60000)}}, nil),
+		d.notifier.EXPECT().Notify(gomock.Any(), int64(42), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ int64, text string) error {
+				if !strings.Contains(text, "BTC изменился на +5.50% за 1ч") {
+					t.Errorf("unexpected notification: %s", text)
+				}
+				return nil
+			}),
+		d.storage.EXPECT().UpdateAlertState(gomock.Any(), int64(9), true, now).Return(nil),
+	)
+	svc.OnPrices(ctx, btcPrice(63300))
+}
+
+func TestOnPrices_MoveBelowThresholdOrWrongDirection(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	// +4% меньше порога, а алерт на падение не реагирует на рост
+	base := []domain.Coin{{Symbol: "BTC", Currency: "usd", Price: dec(60000)}}
+	d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcMove(domain.AlertAny)}, nil)
+	d.history.EXPECT().PricesAt(gomock.Any(), "BTC", "usd", gomock.Any()).Return(base, nil)
+	svc.OnPrices(ctx, btcPrice(62400))
+
+	d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcMove(domain.AlertBelow)}, nil)
+	d.history.EXPECT().PricesAt(gomock.Any(), "BTC", "usd", gomock.Any()).Return(base, nil)
+	svc.OnPrices(ctx, btcPrice(66000))
+}
+
+func TestOnPrices_MoveCooldown(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	// сработал час назад, пауза 2ч — история даже не запрашивается
+	a := btcMove(domain.AlertAny)
+	a.TriggeredAt = now.Add(-time.Hour)
+	d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{a}, nil)
+	svc.OnPrices(ctx, btcPrice(70000))
+
+	// без истории на начало окна изменение не считается
+	d.storage.EXPECT().AlertsBySymbols(gomock.Any(), []string{"BTC"}).Return([]domain.Alert{btcMove(domain.AlertAny)}, nil)
+	d.history.EXPECT().PricesAt(gomock.Any(), "BTC", "usd", gomock.Any()).Return([]domain.Coin{{}}, nil)
+	svc.OnPrices(ctx, btcPrice(70000))
+}
@@ -0,0 +1,268 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/alerts.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, chatID int64, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, chatID, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, chatID, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, chatID, text)
+}
+
+// MockPriceHistory is a mock of PriceHistory interface.
+type MockPriceHistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceHistoryMockRecorder
+}
+
+// MockPriceHistoryMockRecorder is the mock recorder for MockPriceHistory.
+type MockPriceHistoryMockRecorder struct {
+	mock *MockPriceHistory
+}
+
+// NewMockPriceHistory creates a new mock instance.
+func NewMockPriceHistory(ctrl *gomock.Controller) *MockPriceHistory {
+	mock := &MockPriceHistory{ctrl: ctrl}
+	mock.recorder = &MockPriceHistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceHistory) EXPECT() *MockPriceHistoryMockRecorder {
+	return m.recorder
+}
+
+// PricesAt mocks base method.
+func (m *MockPriceHistory) PricesAt(ctx context.Context, symbol, currency string, at []time.Time) ([]domain.Coin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PricesAt", ctx, symbol, currency, at)
+	ret0, _ := ret[0].([]domain.Coin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PricesAt indicates an expected call of PricesAt.
+func (mr *MockPriceHistoryMockRecorder) PricesAt(ctx, symbol, currency, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PricesAt", reflect.TypeOf((*MockPriceHistory)(nil).PricesAt), ctx, symbol, currency, at)
+}
+
+// MockAlertStorage is a mock of AlertStorage interface.
+type MockAlertStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertStorageMockRecorder
+}
+
+// MockAlertStorageMockRecorder is the mock recorder for MockAlertStorage.
+type MockAlertStorageMockRecorder struct {
+	mock *MockAlertStorage
+}
+
+// NewMockAlertStorage creates a new mock instance.
+func NewMockAlertStorage(ctrl *gomock.Controller) *MockAlertStorage {
+	mock := &MockAlertStorage{ctrl: ctrl}
+	mock.recorder = &MockAlertStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertStorage) EXPECT() *MockAlertStorageMockRecorder {
+	return m.recorder
+}
+
+// AlertsBySymbols mocks base method.
+func (m *MockAlertStorage) AlertsBySymbols(ctx context.Context, symbols []string) ([]domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AlertsBySymbols", ctx, symbols)
+	ret0, _ := ret[0].([]domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AlertsBySymbols indicates an expected call of AlertsBySymbols.
+func (mr *MockAlertStorageMockRecorder) AlertsBySymbols(ctx, symbols interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlertsBySymbols", reflect.TypeOf((*MockAlertStorage)(nil).AlertsBySymbols), ctx, symbols)
+}
+
+// CreateAlert mocks base method.
+func (m *MockAlertStorage) CreateAlert(ctx context.Context, a domain.Alert) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlert", ctx, a)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAlert indicates an expected call of CreateAlert.
+func (mr *MockAlertStorageMockRecorder) CreateAlert(ctx, a interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlert", reflect.TypeOf((*MockAlertStorage)(nil).CreateAlert), ctx, a)
+}
+
+// DeleteAlert mocks base method.
+func (m *MockAlertStorage) DeleteAlert(ctx context.Context, chatID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlert", ctx, chatID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlert indicates an expected call of DeleteAlert.
+func (mr *MockAlertStorageMockRecorder) DeleteAlert(ctx, chatID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlert", reflect.TypeOf((*MockAlertStorage)(nil).DeleteAlert), ctx, chatID, id)
+}
+
+// ListAlerts mocks base method.
+func (m *MockAlertStorage) ListAlerts(ctx context.Context, chatID int64) ([]domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlerts", ctx, chatID)
+	ret0, _ := ret[0].([]domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlerts indicates an expected call of ListAlerts.
+func (mr *MockAlertStorageMockRecorder) ListAlerts(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlerts", reflect.TypeOf((*MockAlertStorage)(nil).ListAlerts), ctx, chatID)
+}
+
+// UpdateAlertState mocks base method.
+func (m *MockAlertStorage) UpdateAlertState(ctx context.Context, id int64, armed bool, triggeredAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlertState", ctx, id, armed, triggeredAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAlertState indicates an expected call of UpdateAlertState.
+func (mr *MockAlertStorageMockRecorder) UpdateAlertState(ctx, id, armed, triggeredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlertState", reflect.TypeOf((*MockAlertStorage)(nil).UpdateAlertState), ctx, id, armed, triggeredAt)
+}
+
+// MockAlertCommander is a mock of AlertCommander interface.
+type MockAlertCommander struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertCommanderMockRecorder
+}
+
+// MockAlertCommanderMockRecorder is the mock recorder for MockAlertCommander.
+type MockAlertCommanderMockRecorder struct {
+	mock *MockAlertCommander
+}
+
+// NewMockAlertCommander creates a new mock instance.
+func NewMockAlertCommander(ctrl *gomock.Controller) *MockAlertCommander {
+	mock := &MockAlertCommander{ctrl: ctrl}
+	mock.recorder = &MockAlertCommanderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertCommander) EXPECT() *MockAlertCommanderMockRecorder {
+	return m.recorder
+}
+
+// CreateAlert mocks base method.
+func (m *MockAlertCommander) CreateAlert(ctx context.Context, chatID int64, symbol, currency string, direction domain.AlertDirection, level decimal.Decimal) (domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlert", ctx, chatID, symbol, currency, direction, level)
+	ret0, _ := ret[0].(domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAlert indicates an expected call of CreateAlert.
+func (mr *MockAlertCommanderMockRecorder) CreateAlert(ctx, chatID, symbol, currency, direction, level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlert", reflect.TypeOf((*MockAlertCommander)(nil).CreateAlert), ctx, chatID, symbol, currency, direction, level)
+}
+
+// CreateMoveAlert mocks base method.
+func (m *MockAlertCommander) CreateMoveAlert(ctx context.Context, chatID int64, symbol, currency string, direction domain.AlertDirection, pct decimal.Decimal, window, cooldown time.Duration) (domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMoveAlert", ctx, chatID, symbol, currency, direction, pct, window, cooldown)
+	ret0, _ := ret[0].(domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMoveAlert indicates an expected call of CreateMoveAlert.
+func (mr *MockAlertCommanderMockRecorder) CreateMoveAlert(ctx, chatID, symbol, currency, direction, pct, window, cooldown interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMoveAlert", reflect.TypeOf((*MockAlertCommander)(nil).CreateMoveAlert), ctx, chatID, symbol, currency, direction, pct, window, cooldown)
+}
+
+// DeleteAlert mocks base method.
+func (m *MockAlertCommander) DeleteAlert(ctx context.Context, chatID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlert", ctx, chatID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlert indicates an expected call of DeleteAlert.
+func (mr *MockAlertCommanderMockRecorder) DeleteAlert(ctx, chatID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlert", reflect.TypeOf((*MockAlertCommander)(nil).DeleteAlert), ctx, chatID, id)
+}
+
+// ListAlerts mocks base method.
+func (m *MockAlertCommander) ListAlerts(ctx context.Context, chatID int64) ([]domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlerts", ctx, chatID)
+	ret0, _ := ret[0].([]domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlerts indicates an expected call of ListAlerts.
+func (mr *MockAlertCommanderMockRecorder) ListAlerts(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlerts", reflect.TypeOf((*MockAlertCommander)(nil).ListAlerts), ctx, chatID)
+}
@@ -0,0 +1,179 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultChunk — период одного запроса к провайдеру.
+// До 90 дней CoinGecko отдаёт почасовые точки, дольше — только дневные.
+const DefaultChunk = 90 * 24 * time.Hour
+
+// Options — что загружать
+type Options struct {
+	From, To   time.Time     // To пустой — до текущего момента
+	Symbols    []string      // пусто — все включённые монеты реестра
+	Currencies []string      // валюты котировки
+	Chunk      time.Duration // 0 — DefaultChunk
+}
+
+// Service — загрузка исторических цен в prices.
+// Диапазон режется на куски, после каждого куска сохраняется прогресс по началу диапазона:
+// повторный запуск с тем же From продолжает с места остановки (в том числе без To — до нового «сейчас»),
+// а уже сохранённые точки перезаписываются upsert'ом без дублей.
+// Частоту запросов ограничивает сам history (providers.RateLimited с бюджетом провайдера).
+type Service struct {
+	storage interfaces.BackfillStorage
+	history interfaces.HistoryProvider
+	logger  *slog.Logger
+}
+
+func NewService(storage interfaces.BackfillStorage, history interfaces.HistoryProvider, logger *slog.Logger) *Service {
+	return &Service{
+		storage: storage,
+		history: history,
+		logger:  logger,
+	}
+}
+
+// Run — загружает историю и возвращает число сохранённых точек.
+func (s *Service) Run(ctx context.Context, opts Options) (int, error) {
+	if opts.To.IsZero() {
+		opts.To = utils.NowFunc()
+	}
+	// провайдер принимает unix-секунды; тот же ключ используется для прогресса
+	opts.From = opts.From.UTC().Truncate(time.Second)
+	opts.To = opts.To.UTC().Truncate(time.Second)
+	if opts.From.IsZero() || !opts.From.Before(opts.To) {
+		return 0, fmt.Errorf("%w: from must be before to", errs.ErrInvalidArgument)
+	}
+	if len(opts.Currencies) == 0 {
+		return 0, fmt.Errorf("%w: no currencies", errs.ErrInvalidArgument)
+	}
+	if opts.Chunk <= 0 {
+		opts.Chunk = DefaultChunk
+	}
+
+	coins, err := s.resolveCoins(ctx, opts.Symbols)
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("backfill started",
+		"from", opts.From, "to", opts.To, "coins", len(coins), "currencies", opts.Currencies, "chunk", opts.Chunk)
+
+	var saved int
+	for _, coin := range coins {
+		for _, currency := range opts.Currencies {
+			n, err := s.backfillCoin(ctx, coin, strings.ToLower(currency), opts)
+			saved += n
+			if err != nil {
+				return saved, err
+			}
+		}
+	}
+
+	s.logger.Info("backfill finished", "saved", saved)
+	return saved, nil
+}
+
+// backfillCoin — одна монета в одной валюте, с продолжением по сохранённому прогрессу
+func (s *Service) backfillCoin(ctx context.Context, coin domain.CoinInfo, currency string, opts Options) (int, error) {
+	symbol := strings.ToUpper(coin.Symbol)
+
+	start := opts.From
+	done, err := s.storage.BackfillProgress(ctx, symbol, currency, opts.From)
+	switch {
+	case err == nil:
+		if done.After(start) {
+			start = done
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+	default:
+		return 0, fmt.Errorf("%w: storage.BackfillProgress(%s): %w", errs.ErrInternal, symbol, err)
+	}
+	if !start.Before(opts.To) {
+		s.logger.Info("backfill already complete", "symbol", symbol, "currency", currency)
+		return 0, nil
+	}
+	if start.After(opts.From) {
+		s.logger.Info("backfill resumed", "symbol", symbol, "currency", currency, "from", start)
+	}
+
+	var saved int
+	for chunkFrom := start; chunkFrom.Before(opts.To); {
+		chunkTo := chunkFrom.Add(opts.Chunk)
+		if chunkTo.After(opts.To) {
+			chunkTo = opts.To
+		}
+
+		points, err := s.history.FetchRange(ctx, coin, currency, chunkFrom, chunkTo)
+		if err != nil {
+			s.logger.Error("backfill fetch failed", "symbol", symbol, "currency", currency, "from", chunkFrom, "err", err)
+			return saved, fmt.Errorf("%w: history.FetchRange(%s, %s): %w", errs.ErrInternal, symbol, currency, err)
+		}
+
+		// точки на границе попадают в соседние куски — upsert схлопывает их в одну строку
+		items := make([]domain.Coin, 0, len(points))
+		for _, p := range points {
+			if p.UpdatedAt.Before(chunkFrom) || p.UpdatedAt.After(chunkTo) {
+				continue
+			}
+			p.Symbol = symbol
+			p.Currency = currency
+			items = append(items, p)
+		}
+		if len(items) > 0 {
+			if err := s.storage.SaveCoins(ctx, items); err != nil {
+				return saved, fmt.Errorf("%w: storage.SaveCoins(count=%d): %w", errs.ErrInternal, len(items), err)
+			}
+		}
+		if err := s.storage.SaveBackfillProgress(ctx, symbol, currency, opts.From, chunkTo); err != nil {
+			return saved, fmt.Errorf("%w: storage.SaveBackfillProgress(%s): %w", errs.ErrInternal, symbol, err)
+		}
+
+		saved += len(items)
+		s.logger.Info("backfill chunk saved",
+			"symbol", symbol, "currency", currency, "from", chunkFrom, "to", chunkTo, "count", len(items))
+		chunkFrom = chunkTo
+	}
+	return saved, nil
+}
+
+// resolveCoins — монеты для загрузки: указанные символы или все включённые
+func (s *Service) resolveCoins(ctx context.Context, symbols []string) ([]domain.CoinInfo, error) {
+	if len(symbols) == 0 {
+		coins, err := s.storage.ListCoins(ctx, true)
+		if err != nil {
+			return nil, fmt.Errorf("%w: storage.ListCoins: %w", errs.ErrInternal, err)
+		}
+		if len(coins) == 0 {
+			return nil, fmt.Errorf("%w: no tracked coins in registry", errs.ErrCoinNotFound)
+		}
+		return coins, nil
+	}
+
+	coins := make([]domain.CoinInfo, 0, len(symbols))
+	for _, sym := range symbols {
+		sym = strings.ToUpper(strings.TrimSpace(sym))
+		info, err := s.storage.GetCoinInfo(ctx, sym)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("%w: %s", errs.ErrCoinNotFound, sym)
+			}
+			return nil, fmt.Errorf("%w: storage.GetCoinInfo(%s): %w", errs.ErrInternal, sym, err)
+		}
+		coins = append(coins, info)
+	}
+	return coins, nil
+}
@@ -0,0 +1,171 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	backfillmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/backfill/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	btcInfo = domain.CoinInfo{ProviderID: "bitcoin", Symbol: "BTC", Enabled: true}
+	from    = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// helper to build service with mocks
+func setupSvc(t *testing.T) (context.Context, *gomock.Controller, *backfillmocks.MockBackfillStorage, *backfillmocks.MockHistoryProvider, *Service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	storage := backfillmocks.NewMockBackfillStorage(ctrl)
+	history := backfillmocks.NewMockHistoryProvider(ctrl)
+	return context.Background(), ctrl, storage, history, NewService(storage, history, slog.Default())
+}
+
+// point — историческая цена в момент at
+func point(at time.Time, price int64) domain.Coin {
+	return domain.Coin{Symbol: "BTC", Price: decimal.NewFromInt(price), Currency: "usd", Source: "coingecko", UpdatedAt: at}
+}
+
+func TestRun_ChunksAndSavesProgress(t *testing.T) {
+	ctx, ctrl, storage, history, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	to := from.Add(60 * time.Hour)
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().BackfillProgress(gomock.Any(), "BTC", "usd", from).Return(time.Time{}, pgx.ErrNoRows)
+
+	// 60ч кусками по 24ч: [0,24) [24,48) [48,60)
+	gomock.InOrder(
+		history.EXPECT().FetchRange(gomock.Any(), btcInfo, "usd", from, from.Add(24*time.Hour)).
+			Return([]domain.Coin{point(from, 1), point(from.Add(time.Hour), 2)}, nil),
+		storage.EXPECT().SaveCoins(gomock.Any(), []domain.Coin{point(from, 1), point(from.Add(time.Hour), 2)}).Return(nil),
+		storage.EXPECT().SaveBackfillProgress(gomock.Any(), "BTC", "usd", from, from.Add(24*time.Hour)).Return(nil),
+
+		// пустой кусок — сохранять нечего, но прогресс двигается
+		history.EXPECT().FetchRange(gomock.Any(), btcInfo, "usd", from.Add(24*time.Hour), from.Add(48*time.Hour)).Return(nil, nil),
+		storage.EXPECT().SaveBackfillProgress(gomock.Any(), "BTC", "usd", from, from.Add(48*time.Hour)).Return(nil),
+
+		history.EXPECT().FetchRange(gomock.Any(), btcInfo, "usd", from.Add(48*time.Hour), to).
+			Return([]domain.Coin{point(to, 3)}, nil),
+		storage.EXPECT().SaveCoins(gomock.Any(), []domain.Coin{point(to, 3)}).Return(nil),
+		storage.EXPECT().SaveBackfillProgress(gomock.Any(), "BTC", "usd", from, to).Return(nil),
+	)
+
+	saved, err := svc.Run(ctx, Options{From: from, To: to, Symbols: []string{"btc"}, Currencies: []string{"USD"}, Chunk: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved != 3 {
+		t.Fatalf("expected 3 saved points, got %d", saved)
+	}
+}
+
+func TestRun_ResumesFromProgress(t *testing.T) {
+	ctx, ctrl, storage, history, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	to := from.Add(48 * time.Hour)
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
+	storage.EXPECT().BackfillProgress(gomock.Any(), "BTC", "usd", from).Return(from.Add(24*time.Hour), nil)
+
+	// первый кусок уже загружен предыдущим запуском
+	history.EXPECT().FetchRange(gomock.Any(), btcInfo, "usd", from.Add(24*time.Hour), to).Return(nil, nil)
+	storage.EXPECT().SaveBackfillProgress(gomock.Any(), "BTC", "usd", from, to).Return(nil)
+
+	if _, err := svc.Run(ctx, Options{From: from, To: to, Currencies: []string{"usd"}, Chunk: 24 * time.Hour}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRun_AlreadyComplete(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	to := from.Add(48 * time.Hour)
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
+	storage.EXPECT().BackfillProgress(gomock.Any(), "BTC", "usd", from).Return(to, nil)
+
+	saved, err := svc.Run(ctx, Options{From: from, To: to, Currencies: []string{"usd"}})
+	if err != nil || saved != 0 {
+		t.Fatalf("expected no-op, got %d, %v", saved, err)
+	}
+}
+
+func TestRun_FetchErrorKeepsProgress(t *testing.T) {
+	ctx, ctrl, storage, history, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	to := from.Add(48 * time.Hour)
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
+	storage.EXPECT().BackfillProgress(gomock.Any(), "BTC", "usd", from).Return(time.Time{}, pgx.ErrNoRows)
+	history.EXPECT().FetchRange(gomock.Any(), btcInfo, "usd", from, to).Return(nil, errors.New("429 Too Many Requests"))
+
+	_, err := svc.Run(ctx, Options{From: from, To: to, Currencies: []string{"usd"}, Chunk: 72 * time.Hour})
+	if !errors.Is(err, derrors.ErrInternal) {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+}
+
+func TestRun_WithoutToResumesOnRerun(t *testing.T) {
+	ctx, ctrl, storage, history, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	// -to не задан: конец диапазона — «сейчас», и у второго запуска он другой
+	first := from.Add(24 * time.Hour)
+	second := from.Add(36 * time.Hour)
+	now := first
+	prev := utils.NowFunc
+	utils.NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { utils.NowFunc = prev })
+
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil).Times(2)
+	gomock.InOrder(
+		storage.EXPECT().BackfillProgress(gomock.Any(), "BTC", "usd", from).Return(time.Time{}, pgx.ErrNoRows),
+		history.EXPECT().FetchRange(gomock.Any(), btcInfo, "usd", from, first).Return(nil, nil),
+		storage.EXPECT().SaveBackfillProgress(gomock.Any(), "BTC", "usd", from, first).Return(nil),
+		// повтор находит прогресс первого запуска и догружает только новый хвост
+		storage.EXPECT().BackfillProgress(gomock.Any(), "BTC", "usd", from).Return(first, nil),
+		history.EXPECT().FetchRange(gomock.Any(), btcInfo, "usd", first, second).Return(nil, nil),
+		storage.EXPECT().SaveBackfillProgress(gomock.Any(), "BTC", "usd", from, second).Return(nil),
+	)
+
+	opts := Options{From: from, Currencies: []string{"usd"}}
+	if _, err := svc.Run(ctx, opts); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	now = second
+	if _, err := svc.Run(ctx, opts); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+}
+
+func TestRun_UnknownSymbol(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "NOPE").Return(domain.CoinInfo{}, pgx.ErrNoRows)
+
+	_, err := svc.Run(ctx, Options{From: from, To: from.Add(time.Hour), Symbols: []string{"nope"}, Currencies: []string{"usd"}})
+	if !errors.Is(err, derrors.ErrCoinNotFound) {
+		t.Fatalf("expected ErrCoinNotFound, got %v", err)
+	}
+}
+
+func TestRun_InvalidRange(t *testing.T) {
+	ctx, ctrl, _, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	_, err := svc.Run(ctx, Options{From: from, To: from, Currencies: []string{"usd"}})
+	if !errors.Is(err, derrors.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/backfill.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHistoryProvider is a mock of HistoryProvider interface.
+type MockHistoryProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryProviderMockRecorder
+}
+
+// MockHistoryProviderMockRecorder is the mock recorder for MockHistoryProvider.
+type MockHistoryProviderMockRecorder struct {
+	mock *MockHistoryProvider
+}
+
+// NewMockHistoryProvider creates a new mock instance.
+func NewMockHistoryProvider(ctrl *gomock.Controller) *MockHistoryProvider {
+	mock := &MockHistoryProvider{ctrl: ctrl}
+	mock.recorder = &MockHistoryProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryProvider) EXPECT() *MockHistoryProviderMockRecorder {
+	return m.recorder
+}
+
+// FetchRange mocks base method.
+func (m *MockHistoryProvider) FetchRange(ctx context.Context, coin domain.CoinInfo, currency string, from, to time.Time) ([]domain.Coin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchRange", ctx, coin, currency, from, to)
+	ret0, _ := ret[0].([]domain.Coin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchRange indicates an expected call of FetchRange.
+func (mr *MockHistoryProviderMockRecorder) FetchRange(ctx, coin, currency, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchRange", reflect.TypeOf((*MockHistoryProvider)(nil).FetchRange), ctx, coin, currency, from, to)
+}
+
+// MockBackfillStorage is a mock of BackfillStorage interface.
+type MockBackfillStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackfillStorageMockRecorder
+}
+
+// MockBackfillStorageMockRecorder is the mock recorder for MockBackfillStorage.
+type MockBackfillStorageMockRecorder struct {
+	mock *MockBackfillStorage
+}
+
+// NewMockBackfillStorage creates a new mock instance.
+func NewMockBackfillStorage(ctrl *gomock.Controller) *MockBackfillStorage {
+	mock := &MockBackfillStorage{ctrl: ctrl}
+	mock.recorder = &MockBackfillStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackfillStorage) EXPECT() *MockBackfillStorageMockRecorder {
+	return m.recorder
+}
+
+// BackfillProgress mocks base method.
+func (m *MockBackfillStorage) BackfillProgress(ctx context.Context, symbol, currency string, from time.Time) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillProgress", ctx, symbol, currency, from)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackfillProgress indicates an expected call of BackfillProgress.
+func (mr *MockBackfillStorageMockRecorder) BackfillProgress(ctx, symbol, currency, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillProgress", reflect.TypeOf((*MockBackfillStorage)(nil).BackfillProgress), ctx, symbol, currency, from)
+}
+
+// GetCoinInfo mocks base method.
+func (m *MockBackfillStorage) GetCoinInfo(ctx context.Context, symbol string) (domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoinInfo", ctx, symbol)
+	ret0, _ := ret[0].(domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoinInfo indicates an expected call of GetCoinInfo.
+func (mr *MockBackfillStorageMockRecorder) GetCoinInfo(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinInfo", reflect.TypeOf((*MockBackfillStorage)(nil).GetCoinInfo), ctx, symbol)
+}
+
+// ListCoins mocks base method.
+func (m *MockBackfillStorage) ListCoins(ctx context.Context, enabledOnly bool) ([]domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoins", ctx, enabledOnly)
+	ret0, _ := ret[0].([]domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoins indicates an expected call of ListCoins.
+func (mr *MockBackfillStorageMockRecorder) ListCoins(ctx, enabledOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoins", reflect.TypeOf((*MockBackfillStorage)(nil).ListCoins), ctx, enabledOnly)
+}
+
+// SaveBackfillProgress mocks base method.
+func (m *MockBackfillStorage) SaveBackfillProgress(ctx context.Context, symbol, currency string, from, doneUntil time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBackfillProgress", ctx, symbol, currency, from, doneUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBackfillProgress indicates an expected call of SaveBackfillProgress.
+func (mr *MockBackfillStorageMockRecorder) SaveBackfillProgress(ctx, symbol, currency, from, doneUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBackfillProgress", reflect.TypeOf((*MockBackfillStorage)(nil).SaveBackfillProgress), ctx, symbol, currency, from, doneUntil)
+}
+
+// SaveCoins mocks base method.
+func (m *MockBackfillStorage) SaveCoins(ctx context.Context, items []domain.Coin) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCoins", ctx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCoins indicates an expected call of SaveCoins.
+func (mr *MockBackfillStorageMockRecorder) SaveCoins(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCoins", reflect.TypeOf((*MockBackfillStorage)(nil).SaveCoins), ctx, items)
+}
@@ -0,0 +1,125 @@
+package coins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// pgUniqueViolation — код ошибки Postgres при нарушении уникальности
+const pgUniqueViolation = "23505"
+
+type Service struct {
+	registry interfaces.CoinRegistryStore
+	lookup   interfaces.CoinLookup
+	logger   *slog.Logger
+}
+
+func NewService(registry interfaces.CoinRegistryStore, lookup interfaces.CoinLookup, logger *slog.Logger) *Service {
+	return &Service{
+		registry: registry,
+		lookup:   lookup,
+		logger:   logger,
+	}
+}
+
+// ListCoins — все монеты реестра, включая выключенные.
+func (s *Service) ListCoins(ctx context.Context) ([]domain.CoinInfo, error) {
+	items, err := s.registry.ListCoins(ctx, false)
+	if err != nil {
+		s.logger.Error("failed to list coins", "err", err)
+		return nil, fmt.Errorf("%w: registry.ListCoins: %w", errs.ErrInternal, err)
+	}
+	return items, nil
+}
+
+// AddCoin — проверяет providerID в каталоге провайдера и добавляет монету в реестр.
+// symbol и name необязательны: по умолчанию берутся из каталога провайдера.
+func (s *Service) AddCoin(ctx context.Context, providerID, symbol, name string) (domain.CoinInfo, error) {
+	providerID = strings.ToLower(strings.TrimSpace(providerID))
+	if providerID == "" {
+		return domain.CoinInfo{}, fmt.Errorf("%w: provider_id is required", errs.ErrInvalidArgument)
+	}
+
+	upstream, err := s.lookup.LookupCoin(ctx, providerID)
+	if err != nil {
+		if errors.Is(err, errs.ErrUnknownProviderID) {
+			s.logger.Warn("unknown provider id", "provider_id", providerID)
+			return domain.CoinInfo{}, err
+		}
+		if errors.Is(err, errs.ErrRateLimited) {
+			// бюджет запросов общий с загрузкой курсов — проверка откладывается, а не падает
+			s.logger.Warn("provider lookup rate limited", "provider_id", providerID, "err", err)
+			return domain.CoinInfo{}, err
+		}
+		s.logger.Error("provider lookup failed", "provider_id", providerID, "err", err)
+		return domain.CoinInfo{}, fmt.Errorf("%w: lookup.LookupCoin(%s): %w", errs.ErrInternal, providerID, err)
+	}
+
+	coin := domain.CoinInfo{
+		ProviderID: upstream.ProviderID,
+		Symbol:     strings.ToUpper(strings.TrimSpace(symbol)),
+		Name:       strings.TrimSpace(name),
+		Enabled:    true,
+	}
+	if coin.Symbol == "" {
+		coin.Symbol = upstream.Symbol
+	}
+	if coin.Name == "" {
+		coin.Name = upstream.Name
+	}
+
+	if err := s.registry.AddCoin(ctx, coin); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			s.logger.Warn("coin already exists", "symbol", coin.Symbol, "provider_id", coin.ProviderID)
+			return domain.CoinInfo{}, errs.ErrCoinAlreadyExists
+		}
+		s.logger.Error("failed to add coin", "symbol", coin.Symbol, "err", err)
+		return domain.CoinInfo{}, fmt.Errorf("%w: registry.AddCoin(%s): %w", errs.ErrInternal, coin.Symbol, err)
+	}
+	s.logger.Info("coin added", "symbol", coin.Symbol, "provider_id", coin.ProviderID)
+	return coin, nil
+}
+
+// UpdateCoin — включает/выключает монету и/или меняет её название.
+func (s *Service) UpdateCoin(ctx context.Context, symbol string, enabled *bool, name *string) (domain.CoinInfo, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if enabled == nil && name == nil {
+		return domain.CoinInfo{}, fmt.Errorf("%w: nothing to update", errs.ErrInvalidArgument)
+	}
+
+	coin, err := s.registry.UpdateCoin(ctx, symbol, enabled, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CoinInfo{}, errs.ErrCoinNotFound
+		}
+		s.logger.Error("failed to update coin", "symbol", symbol, "err", err)
+		return domain.CoinInfo{}, fmt.Errorf("%w: registry.UpdateCoin(%s): %w", errs.ErrInternal, symbol, err)
+	}
+	s.logger.Info("coin updated", "symbol", coin.Symbol, "enabled", coin.Enabled)
+	return coin, nil
+}
+
+// RemoveCoin — удаляет монету из реестра вместе с историей цен.
+func (s *Service) RemoveCoin(ctx context.Context, symbol string) error {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	if err := s.registry.DeleteCoin(ctx, symbol); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errs.ErrCoinNotFound
+		}
+		s.logger.Error("failed to delete coin", "symbol", symbol, "err", err)
+		return fmt.Errorf("%w: registry.DeleteCoin(%s): %w", errs.ErrInternal, symbol, err)
+	}
+	s.logger.Info("coin removed", "symbol", symbol)
+	return nil
+}
@@ -0,0 +1,133 @@
+package coins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	coinsmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/coins/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// helper to build service with mocks
+func setupSvc(t *testing.T) (context.Context, *gomock.Controller, *coinsmocks.MockCoinRegistryStore, *coinsmocks.MockCoinLookup, *Service) {
+	t.Helper()
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	registry := coinsmocks.NewMockCoinRegistryStore(ctrl)
+	lookup := coinsmocks.NewMockCoinLookup(ctrl)
+	svc := NewService(registry, lookup, slog.Default())
+	return ctx, ctrl, registry, lookup, svc
+}
+
+var solana = domain.CoinInfo{ProviderID: "solana", Symbol: "SOL", Name: "Solana", Enabled: true}
+
+func TestAddCoin_DefaultsFromProvider(t *testing.T) {
+	ctx, ctrl, registry, lookup, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	lookup.EXPECT().LookupCoin(gomock.Any(), "solana").Return(solana, nil)
+	registry.EXPECT().AddCoin(gomock.Any(), solana).Return(nil)
+
+	got, err := svc.AddCoin(ctx, " Solana ", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != solana {
+		t.Fatalf("unexpected coin: %+v", got)
+	}
+}
+
+func TestAddCoin_SymbolOverride(t *testing.T) {
+	ctx, ctrl, registry, lookup, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	want := domain.CoinInfo{ProviderID: "solana", Symbol: "SOLX", Name: "Solana", Enabled: true}
+	lookup.EXPECT().LookupCoin(gomock.Any(), "solana").Return(solana, nil)
+	registry.EXPECT().AddCoin(gomock.Any(), want).Return(nil)
+
+	got, err := svc.AddCoin(ctx, "solana", "solx", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("unexpected coin: %+v", got)
+	}
+}
+
+func TestAddCoin_EmptyProviderID(t *testing.T) {
+	ctx, ctrl, _, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	_, err := svc.AddCoin(ctx, "  ", "", "")
+	if err == nil || !errors.Is(err, derrors.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestAddCoin_UnknownProviderID(t *testing.T) {
+	ctx, ctrl, _, lookup, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	lookup.EXPECT().LookupCoin(gomock.Any(), "nope").
+		Return(domain.CoinInfo{}, fmt.Errorf("%w: nope", derrors.ErrUnknownProviderID))
+
+	_, err := svc.AddCoin(ctx, "nope", "", "")
+	if err == nil || !errors.Is(err, derrors.ErrUnknownProviderID) {
+		t.Fatalf("expected ErrUnknownProviderID, got %v", err)
+	}
+}
+
+func TestAddCoin_LookupRateLimited(t *testing.T) {
+	ctx, ctrl, _, lookup, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	lookup.EXPECT().LookupCoin(gomock.Any(), "solana").
+		Return(domain.CoinInfo{}, fmt.Errorf("coingecko: %w", derrors.ErrRateLimited))
+
+	_, err := svc.AddCoin(ctx, "solana", "", "")
+	if !errors.Is(err, derrors.ErrRateLimited) || errors.Is(err, derrors.ErrInternal) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestAddCoin_AlreadyExists(t *testing.T) {
+	ctx, ctrl, registry, lookup, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	lookup.EXPECT().LookupCoin(gomock.Any(), "solana").Return(solana, nil)
+	registry.EXPECT().AddCoin(gomock.Any(), solana).Return(&pgconn.PgError{Code: pgUniqueViolation})
+
+	_, err := svc.AddCoin(ctx, "solana", "", "")
+	if err == nil || !errors.Is(err, derrors.ErrCoinAlreadyExists) {
+		t.Fatalf("expected ErrCoinAlreadyExists, got %v", err)
+	}
+}
+
+func TestUpdateCoin_NothingToUpdate(t *testing.T) {
+	ctx, ctrl, _, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	_, err := svc.UpdateCoin(ctx, "SOL", nil, nil)
+	if err == nil || !errors.Is(err, derrors.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestRemoveCoin_NotFound(t *testing.T) {
+	ctx, ctrl, registry, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	registry.EXPECT().DeleteCoin(gomock.Any(), "SOL").Return(pgx.ErrNoRows)
+
+	err := svc.RemoveCoin(ctx, "sol")
+	if err == nil || !errors.Is(err, derrors.ErrCoinNotFound) {
+		t.Fatalf("expected ErrCoinNotFound, got %v", err)
+	}
+}
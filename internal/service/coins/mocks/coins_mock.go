@@ -0,0 +1,229 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/coins.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCoinLookup is a mock of CoinLookup interface.
+type MockCoinLookup struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoinLookupMockRecorder
+}
+
+// MockCoinLookupMockRecorder is the mock recorder for MockCoinLookup.
+type MockCoinLookupMockRecorder struct {
+	mock *MockCoinLookup
+}
+
+// NewMockCoinLookup creates a new mock instance.
+func NewMockCoinLookup(ctrl *gomock.Controller) *MockCoinLookup {
+	mock := &MockCoinLookup{ctrl: ctrl}
+	mock.recorder = &MockCoinLookupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoinLookup) EXPECT() *MockCoinLookupMockRecorder {
+	return m.recorder
+}
+
+// LookupCoin mocks base method.
+func (m *MockCoinLookup) LookupCoin(ctx context.Context, providerID string) (domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupCoin", ctx, providerID)
+	ret0, _ := ret[0].(domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupCoin indicates an expected call of LookupCoin.
+func (mr *MockCoinLookupMockRecorder) LookupCoin(ctx, providerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupCoin", reflect.TypeOf((*MockCoinLookup)(nil).LookupCoin), ctx, providerID)
+}
+
+// MockCoinRegistryStore is a mock of CoinRegistryStore interface.
+type MockCoinRegistryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoinRegistryStoreMockRecorder
+}
+
+// MockCoinRegistryStoreMockRecorder is the mock recorder for MockCoinRegistryStore.
+type MockCoinRegistryStoreMockRecorder struct {
+	mock *MockCoinRegistryStore
+}
+
+// NewMockCoinRegistryStore creates a new mock instance.
+func NewMockCoinRegistryStore(ctrl *gomock.Controller) *MockCoinRegistryStore {
+	mock := &MockCoinRegistryStore{ctrl: ctrl}
+	mock.recorder = &MockCoinRegistryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoinRegistryStore) EXPECT() *MockCoinRegistryStoreMockRecorder {
+	return m.recorder
+}
+
+// AddCoin mocks base method.
+func (m *MockCoinRegistryStore) AddCoin(ctx context.Context, coin domain.CoinInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCoin", ctx, coin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCoin indicates an expected call of AddCoin.
+func (mr *MockCoinRegistryStoreMockRecorder) AddCoin(ctx, coin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCoin", reflect.TypeOf((*MockCoinRegistryStore)(nil).AddCoin), ctx, coin)
+}
+
+// DeleteCoin mocks base method.
+func (m *MockCoinRegistryStore) DeleteCoin(ctx context.Context, symbol string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCoin", ctx, symbol)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCoin indicates an expected call of DeleteCoin.
+func (mr *MockCoinRegistryStoreMockRecorder) DeleteCoin(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCoin", reflect.TypeOf((*MockCoinRegistryStore)(nil).DeleteCoin), ctx, symbol)
+}
+
+// GetCoinInfo mocks base method.
+func (m *MockCoinRegistryStore) GetCoinInfo(ctx context.Context, symbol string) (domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoinInfo", ctx, symbol)
+	ret0, _ := ret[0].(domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoinInfo indicates an expected call of GetCoinInfo.
+func (mr *MockCoinRegistryStoreMockRecorder) GetCoinInfo(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinInfo", reflect.TypeOf((*MockCoinRegistryStore)(nil).GetCoinInfo), ctx, symbol)
+}
+
+// ListCoins mocks base method.
+func (m *MockCoinRegistryStore) ListCoins(ctx context.Context, enabledOnly bool) ([]domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoins", ctx, enabledOnly)
+	ret0, _ := ret[0].([]domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoins indicates an expected call of ListCoins.
+func (mr *MockCoinRegistryStoreMockRecorder) ListCoins(ctx, enabledOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoins", reflect.TypeOf((*MockCoinRegistryStore)(nil).ListCoins), ctx, enabledOnly)
+}
+
+// UpdateCoin mocks base method.
+func (m *MockCoinRegistryStore) UpdateCoin(ctx context.Context, symbol string, enabled *bool, name *string) (domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCoin", ctx, symbol, enabled, name)
+	ret0, _ := ret[0].(domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCoin indicates an expected call of UpdateCoin.
+func (mr *MockCoinRegistryStoreMockRecorder) UpdateCoin(ctx, symbol, enabled, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCoin", reflect.TypeOf((*MockCoinRegistryStore)(nil).UpdateCoin), ctx, symbol, enabled, name)
+}
+
+// MockCoinAdmin is a mock of CoinAdmin interface.
+type MockCoinAdmin struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoinAdminMockRecorder
+}
+
+// MockCoinAdminMockRecorder is the mock recorder for MockCoinAdmin.
+type MockCoinAdminMockRecorder struct {
+	mock *MockCoinAdmin
+}
+
+// NewMockCoinAdmin creates a new mock instance.
+func NewMockCoinAdmin(ctrl *gomock.Controller) *MockCoinAdmin {
+	mock := &MockCoinAdmin{ctrl: ctrl}
+	mock.recorder = &MockCoinAdminMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoinAdmin) EXPECT() *MockCoinAdminMockRecorder {
+	return m.recorder
+}
+
+// AddCoin mocks base method.
+func (m *MockCoinAdmin) AddCoin(ctx context.Context, providerID, symbol, name string) (domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCoin", ctx, providerID, symbol, name)
+	ret0, _ := ret[0].(domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCoin indicates an expected call of AddCoin.
+func (mr *MockCoinAdminMockRecorder) AddCoin(ctx, providerID, symbol, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCoin", reflect.TypeOf((*MockCoinAdmin)(nil).AddCoin), ctx, providerID, symbol, name)
+}
+
+// ListCoins mocks base method.
+func (m *MockCoinAdmin) ListCoins(ctx context.Context) ([]domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoins", ctx)
+	ret0, _ := ret[0].([]domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoins indicates an expected call of ListCoins.
+func (mr *MockCoinAdminMockRecorder) ListCoins(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoins", reflect.TypeOf((*MockCoinAdmin)(nil).ListCoins), ctx)
+}
+
+// RemoveCoin mocks base method.
+func (m *MockCoinAdmin) RemoveCoin(ctx context.Context, symbol string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCoin", ctx, symbol)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCoin indicates an expected call of RemoveCoin.
+func (mr *MockCoinAdminMockRecorder) RemoveCoin(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCoin", reflect.TypeOf((*MockCoinAdmin)(nil).RemoveCoin), ctx, symbol)
+}
+
+// UpdateCoin mocks base method.
+func (m *MockCoinAdmin) UpdateCoin(ctx context.Context, symbol string, enabled *bool, name *string) (domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCoin", ctx, symbol, enabled, name)
+	ret0, _ := ret[0].(domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCoin indicates an expected call of UpdateCoin.
+func (mr *MockCoinAdminMockRecorder) UpdateCoin(ctx, symbol, enabled, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCoin", reflect.TypeOf((*MockCoinAdmin)(nil).UpdateCoin), ctx, symbol, enabled, name)
+}
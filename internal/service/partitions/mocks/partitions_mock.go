@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/partitions.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPartitionMaintainer is a mock of PartitionMaintainer interface.
+type MockPartitionMaintainer struct {
+	ctrl     *gomock.Controller
+	recorder *MockPartitionMaintainerMockRecorder
+}
+
+// MockPartitionMaintainerMockRecorder is the mock recorder for MockPartitionMaintainer.
+type MockPartitionMaintainerMockRecorder struct {
+	mock *MockPartitionMaintainer
+}
+
+// NewMockPartitionMaintainer creates a new mock instance.
+func NewMockPartitionMaintainer(ctrl *gomock.Controller) *MockPartitionMaintainer {
+	mock := &MockPartitionMaintainer{ctrl: ctrl}
+	mock.recorder = &MockPartitionMaintainerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPartitionMaintainer) EXPECT() *MockPartitionMaintainerMockRecorder {
+	return m.recorder
+}
+
+// MaintainPartitions mocks base method.
+func (m *MockPartitionMaintainer) MaintainPartitions(ctx context.Context) (domain.PartitionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaintainPartitions", ctx)
+	ret0, _ := ret[0].(domain.PartitionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaintainPartitions indicates an expected call of MaintainPartitions.
+func (mr *MockPartitionMaintainerMockRecorder) MaintainPartitions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaintainPartitions", reflect.TypeOf((*MockPartitionMaintainer)(nil).MaintainPartitions), ctx)
+}
+
+// MockPartitionStorage is a mock of PartitionStorage interface.
+type MockPartitionStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockPartitionStorageMockRecorder
+}
+
+// MockPartitionStorageMockRecorder is the mock recorder for MockPartitionStorage.
+type MockPartitionStorageMockRecorder struct {
+	mock *MockPartitionStorage
+}
+
+// NewMockPartitionStorage creates a new mock instance.
+func NewMockPartitionStorage(ctrl *gomock.Controller) *MockPartitionStorage {
+	mock := &MockPartitionStorage{ctrl: ctrl}
+	mock.recorder = &MockPartitionStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPartitionStorage) EXPECT() *MockPartitionStorageMockRecorder {
+	return m.recorder
+}
+
+// CreatePartition mocks base method.
+func (m *MockPartitionStorage) CreatePartition(ctx context.Context, p domain.Partition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePartition", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePartition indicates an expected call of CreatePartition.
+func (mr *MockPartitionStorageMockRecorder) CreatePartition(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePartition", reflect.TypeOf((*MockPartitionStorage)(nil).CreatePartition), ctx, p)
+}
+
+// DetachPartition mocks base method.
+func (m *MockPartitionStorage) DetachPartition(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachPartition", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachPartition indicates an expected call of DetachPartition.
+func (mr *MockPartitionStorageMockRecorder) DetachPartition(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachPartition", reflect.TypeOf((*MockPartitionStorage)(nil).DetachPartition), ctx, name)
+}
+
+// DropPartition mocks base method.
+func (m *MockPartitionStorage) DropPartition(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropPartition", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropPartition indicates an expected call of DropPartition.
+func (mr *MockPartitionStorageMockRecorder) DropPartition(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropPartition", reflect.TypeOf((*MockPartitionStorage)(nil).DropPartition), ctx, name)
+}
+
+// ListPartitions mocks base method.
+func (m *MockPartitionStorage) ListPartitions(ctx context.Context) ([]domain.Partition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPartitions", ctx)
+	ret0, _ := ret[0].([]domain.Partition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPartitions indicates an expected call of ListPartitions.
+func (mr *MockPartitionStorageMockRecorder) ListPartitions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPartitions", reflect.TypeOf((*MockPartitionStorage)(nil).ListPartitions), ctx)
+}
@@ -0,0 +1,100 @@
+package partitions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+)
+
+// Policy — обслуживание месячных секций prices.
+type Policy struct {
+	Ahead  int           // сколько месяцев после текущего держать созданными заранее
+	Keep   time.Duration // секция истекает, когда её конец старше Keep; 0 — секции не истекают
+	Detach bool          // истёкшие секции отсоединять (true) или удалять вместе с данными (false)
+}
+
+// Validate — проверка параметров политики
+func (p Policy) Validate() error {
+	if p.Ahead < 0 {
+		return errors.New("partitions: ahead must not be negative")
+	}
+	if p.Keep < 0 {
+		return errors.New("partitions: keep must not be negative")
+	}
+	return nil
+}
+
+type Service struct {
+	storage interfaces.PartitionStorage
+	policy  Policy
+	logger  *slog.Logger
+}
+
+func NewService(storage interfaces.PartitionStorage, policy Policy, logger *slog.Logger) *Service {
+	return &Service{storage: storage, policy: policy, logger: logger}
+}
+
+// MaintainPartitions — создать секции текущего и policy.Ahead следующих месяцев
+// и отсоединить (или удалить) истёкшие. Секция текущего месяца не истекает никогда.
+func (s *Service) MaintainPartitions(ctx context.Context) (domain.PartitionReport, error) {
+	var report domain.PartitionReport
+	now := utils.NowFunc()
+
+	existing, err := s.storage.ListPartitions(ctx)
+	if err != nil {
+		s.logger.Error("list partitions failed", "err", err)
+		return report, fmt.Errorf("%w: storage.ListPartitions: %w", errs.ErrInternal, err)
+	}
+	have := make(map[string]bool, len(existing))
+	for _, p := range existing {
+		have[p.Name] = true
+	}
+
+	// от начала месяца: AddDate от 31-го числа перескочил бы через короткий месяц
+	month := domain.MonthPartition(now).From
+	for i := 0; i <= s.policy.Ahead; i++ {
+		p := domain.MonthPartition(month.AddDate(0, i, 0))
+		if have[p.Name] {
+			continue
+		}
+		if err := s.storage.CreatePartition(ctx, p); err != nil {
+			s.logger.Error("create partition failed", "partition", p.Name, "err", err)
+			return report, fmt.Errorf("%w: storage.CreatePartition(%s): %w", errs.ErrInternal, p.Name, err)
+		}
+		s.logger.Info("partition created", "partition", p.Name, "from", p.From, "to", p.To)
+		report.Created = append(report.Created, p.Name)
+	}
+
+	if s.policy.Keep == 0 {
+		return report, nil
+	}
+	cutoff := now.Add(-s.policy.Keep)
+	for _, p := range existing {
+		if p.To.After(cutoff) {
+			continue
+		}
+		if s.policy.Detach {
+			if err := s.storage.DetachPartition(ctx, p.Name); err != nil {
+				s.logger.Error("detach partition failed", "partition", p.Name, "err", err)
+				return report, fmt.Errorf("%w: storage.DetachPartition(%s): %w", errs.ErrInternal, p.Name, err)
+			}
+			s.logger.Info("partition detached", "partition", p.Name)
+			report.Detached = append(report.Detached, p.Name)
+			continue
+		}
+		if err := s.storage.DropPartition(ctx, p.Name); err != nil {
+			s.logger.Error("drop partition failed", "partition", p.Name, "err", err)
+			return report, fmt.Errorf("%w: storage.DropPartition(%s): %w", errs.ErrInternal, p.Name, err)
+		}
+		s.logger.Info("partition dropped", "partition", p.Name)
+		report.Dropped = append(report.Dropped, p.Name)
+	}
+	return report, nil
+}
@@ -0,0 +1,121 @@
+package partitions
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	partitionmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/partitions/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+const day = 24 * time.Hour
+
+// 31-е число: прибавление месяцев от текущей даты перескочило бы через ноябрь
+var now = time.Date(2025, 10, 31, 12, 0, 0, 0, time.UTC)
+
+// helper to build service with mocks
+func setupSvc(t *testing.T, policy Policy) (context.Context, *gomock.Controller, *partitionmocks.MockPartitionStorage, *Service) {
+	t.Helper()
+	prev := utils.NowFunc
+	utils.NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { utils.NowFunc = prev })
+
+	ctrl := gomock.NewController(t)
+	storage := partitionmocks.NewMockPartitionStorage(ctrl)
+	return context.Background(), ctrl, storage, NewService(storage, policy, slog.Default())
+}
+
+func month(year int, m time.Month) domain.Partition {
+	return domain.MonthPartition(time.Date(year, m, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestMaintainPartitions_CreatesAhead(t *testing.T) {
+	ctx, ctrl, storage, svc := setupSvc(t, Policy{Ahead: 2})
+	defer ctrl.Finish()
+
+	storage.EXPECT().ListPartitions(gomock.Any()).Return([]domain.Partition{month(2025, time.October)}, nil)
+	gomock.InOrder(
+		storage.EXPECT().CreatePartition(gomock.Any(), month(2025, time.November)).Return(nil),
+		storage.EXPECT().CreatePartition(gomock.Any(), month(2025, time.December)).Return(nil),
+	)
+
+	report, err := svc.MaintainPartitions(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Created) != 2 || report.Created[0] != "prices_2025_11" || report.Created[1] != "prices_2025_12" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestMaintainPartitions_ExpireDetach(t *testing.T) {
+	ctx, ctrl, storage, svc := setupSvc(t, Policy{Keep: 60 * day, Detach: true})
+	defer ctrl.Finish()
+
+	// август закончился 1 сентября — больше 60 дней назад; сентябрь ещё хранится
+	storage.EXPECT().ListPartitions(gomock.Any()).Return([]domain.Partition{
+		month(2025, time.August), month(2025, time.September), month(2025, time.October),
+	}, nil)
+	storage.EXPECT().DetachPartition(gomock.Any(), "prices_2025_08").Return(nil)
+
+	report, err := svc.MaintainPartitions(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Detached) != 1 || len(report.Dropped) != 0 || len(report.Created) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestMaintainPartitions_ExpireDrop(t *testing.T) {
+	ctx, ctrl, storage, svc := setupSvc(t, Policy{Keep: 30 * day})
+	defer ctrl.Finish()
+
+	storage.EXPECT().ListPartitions(gomock.Any()).Return([]domain.Partition{
+		month(2025, time.August), month(2025, time.September), month(2025, time.October),
+	}, nil)
+	gomock.InOrder(
+		storage.EXPECT().DropPartition(gomock.Any(), "prices_2025_08").Return(nil),
+		storage.EXPECT().DropPartition(gomock.Any(), "prices_2025_09").Return(nil),
+	)
+
+	report, err := svc.MaintainPartitions(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Dropped) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestMaintainPartitions_StorageError(t *testing.T) {
+	ctx, ctrl, storage, svc := setupSvc(t, Policy{Ahead: 1, Keep: 30 * day})
+	defer ctrl.Finish()
+
+	// после ошибки создания секции истёкшие не трогаются
+	storage.EXPECT().ListPartitions(gomock.Any()).Return([]domain.Partition{month(2025, time.August)}, nil)
+	storage.EXPECT().CreatePartition(gomock.Any(), month(2025, time.October)).Return(errors.New("db down"))
+
+	if _, err := svc.MaintainPartitions(ctx); !errors.Is(err, derrors.ErrInternal) {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	for _, p := range []Policy{{}, {Ahead: 3, Keep: 365 * day, Detach: true}} {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", p, err)
+		}
+	}
+	for _, p := range []Policy{{Ahead: -1}, {Keep: -day}} {
+		if err := p.Validate(); err == nil {
+			t.Errorf("%+v: expected error", p)
+		}
+	}
+}
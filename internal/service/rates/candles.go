@@ -0,0 +1,68 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+)
+
+// CandleIntervals — поддерживаемые интервалы свечей
+var CandleIntervals = map[string]time.Duration{
+	"1m": time.Minute,
+	"5m": 5 * time.Minute,
+	"1h": time.Hour,
+	"1d": 24 * time.Hour,
+}
+
+const (
+	// defaultCandles — сколько свечей отдавать, если период не задан
+	defaultCandles = 100
+	// maxCandles — ограничение на число свечей в одном ответе
+	maxCandles = 5000
+)
+
+// Candles — OHLC-свечи монеты за период [from, to).
+// Пустой to — текущий момент, пустой from — defaultCandles интервалов до to.
+func (s *Service) Candles(ctx context.Context, symbol, currency, interval string, from, to time.Time) ([]domain.Candle, error) {
+	symbol = strings.ToUpper(symbol)
+	step, ok := CandleIntervals[strings.ToLower(interval)]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown interval %q", errs.ErrInvalidArgument, interval)
+	}
+	currency, err := s.resolveCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if to.IsZero() {
+		to = utils.NowFunc()
+	}
+	to = to.UTC()
+	if from.IsZero() {
+		from = to.Add(-defaultCandles * step).Truncate(step)
+	}
+	from = from.UTC()
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", errs.ErrInvalidArgument)
+	}
+	if to.Sub(from)/step > maxCandles {
+		return nil, fmt.Errorf("%w: range exceeds %d candles", errs.ErrInvalidArgument, maxCandles)
+	}
+
+	if err := s.checkTracked(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	candles, err := s.storage.Candles(ctx, symbol, currency, step, from, to)
+	if err != nil {
+		s.logger.Error("failed to load candles", "symbol", symbol, "interval", interval, "err", err)
+		return nil, fmt.Errorf("%w: storage.Candles(%s): %w", errs.ErrInternal, symbol, err)
+	}
+	s.logger.Debug("candles loaded", "symbol", symbol, "currency", currency, "interval", interval, "count", len(candles))
+	return candles, nil
+}
@@ -0,0 +1,85 @@
+package rates
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCandles_Success(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+	want := []domain.Candle{
+		{Time: from, Open: dec(100), High: dec(110), Low: dec(95), Close: dec(105), Points: 12},
+	}
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().Candles(gomock.Any(), "BTC", "usd", time.Hour, from, to).Return(want, nil)
+
+	got, err := svc.Candles(ctx, "btc", "", "1H", from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || !got[0].Close.Equal(dec(105)) {
+		t.Fatalf("unexpected candles: %+v", got)
+	}
+}
+
+func TestCandles_DefaultWindow(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 9, 1, 12, 34, 0, 0, time.UTC)
+	prev := utils.NowFunc
+	utils.NowFunc = func() time.Time { return now }
+	defer func() { utils.NowFunc = prev }()
+
+	// 100 пятиминутных свечей до now, начало выровнено по интервалу
+	wantFrom := time.Date(2025, 9, 1, 4, 10, 0, 0, time.UTC)
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().Candles(gomock.Any(), "BTC", "usd", 5*time.Minute, wantFrom, now).Return(nil, nil)
+
+	if _, err := svc.Candles(ctx, "BTC", "usd", "5m", time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCandles_InvalidArguments(t *testing.T) {
+	ctx, ctrl, _, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		interval string
+		from, to time.Time
+	}{
+		{"unknown interval", "2h", from, from.Add(time.Hour)},
+		{"reversed range", "1h", from, from.Add(-time.Hour)},
+		{"too many candles", "1m", from, from.Add(30 * 24 * time.Hour)},
+	}
+	for _, tc := range cases {
+		if _, err := svc.Candles(ctx, "BTC", "", tc.interval, tc.from, tc.to); !errors.Is(err, derrors.ErrInvalidArgument) {
+			t.Errorf("%s: expected ErrInvalidArgument, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestCandles_CoinNotFound(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(domain.CoinInfo{Symbol: "BTC", Enabled: false}, nil)
+
+	_, err := svc.Candles(ctx, "BTC", "", "1d", time.Time{}, time.Time{})
+	if !errors.Is(err, derrors.ErrCoinNotFound) {
+		t.Fatalf("expected ErrCoinNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+)
+
+const (
+	// DefaultHistoryLimit — размер страницы истории, если limit не задан
+	DefaultHistoryLimit = 500
+	// MaxHistoryLimit — максимальный размер страницы истории
+	MaxHistoryLimit = 5000
+)
+
+// HistoryPage — сырые цены монеты за [from, to) постранично.
+// Пустой to — текущий момент, пустой from — сутки до to, limit <= 0 — DefaultHistoryLimit.
+// after — курсор: время и источник последней цены предыдущей страницы (нулевой — первая страница).
+func (s *Service) HistoryPage(ctx context.Context, symbol, currency string, from, to time.Time, after domain.HistoryCursor, limit int) ([]domain.Coin, domain.HistoryCursor, error) {
+	symbol = strings.ToUpper(symbol)
+	currency, err := s.resolveCurrency(currency)
+	if err != nil {
+		return nil, domain.HistoryCursor{}, err
+	}
+
+	if to.IsZero() {
+		to = utils.NowFunc()
+	}
+	to = to.UTC()
+	if from.IsZero() {
+		from = to.Add(-24 * time.Hour)
+	}
+	from = from.UTC()
+	if !from.Before(to) {
+		return nil, domain.HistoryCursor{}, fmt.Errorf("%w: from must be before to", errs.ErrInvalidArgument)
+	}
+	switch {
+	case limit == 0:
+		limit = DefaultHistoryLimit
+	case limit < 0 || limit > MaxHistoryLimit:
+		return nil, domain.HistoryCursor{}, fmt.Errorf("%w: limit must be between 1 and %d", errs.ErrInvalidArgument, MaxHistoryLimit)
+	}
+
+	if err := s.checkTracked(ctx, symbol); err != nil {
+		return nil, domain.HistoryCursor{}, err
+	}
+
+	// Берём на одну цену больше: так видно, есть ли следующая страница, без COUNT(*)
+	after.Time = after.Time.UTC()
+	rows, err := s.storage.HistoryPage(ctx, symbol, currency, from, to, after, limit+1)
+	if err != nil {
+		s.logger.Error("failed to load history page", "symbol", symbol, "currency", currency, "err", err)
+		return nil, domain.HistoryCursor{}, fmt.Errorf("%w: storage.HistoryPage(%s): %w", errs.ErrInternal, symbol, err)
+	}
+
+	var next domain.HistoryCursor
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		next = domain.HistoryCursor{Time: last.UpdatedAt, Source: last.Source}
+	}
+	s.logger.Debug("history page loaded", "symbol", symbol, "currency", currency, "count", len(rows), "has_next", !next.IsZero())
+	return rows, next, nil
+}
@@ -0,0 +1,76 @@
+package rates
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHistoryPage_NextCursor(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	rows := []domain.Coin{
+		{Symbol: "BTC", Price: dec(100), Source: "coingecko", UpdatedAt: from},
+		{Symbol: "BTC", Price: dec(101), Source: "coingecko", UpdatedAt: from.Add(time.Minute)},
+		{Symbol: "BTC", Price: dec(102), Source: "coingecko", UpdatedAt: from.Add(2 * time.Minute)},
+	}
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	// на одну строку больше лимита — признак следующей страницы
+	storage.EXPECT().HistoryPage(gomock.Any(), "BTC", "usd", from, to, domain.HistoryCursor{}, 3).Return(rows, nil)
+
+	got, next, err := svc.HistoryPage(ctx, "btc", "", from, to, domain.HistoryCursor{}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || !next.Time.Equal(from.Add(time.Minute)) || next.Source != "coingecko" {
+		t.Fatalf("unexpected page: %+v next=%+v", got, next)
+	}
+}
+
+func TestHistoryPage_LastPage(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	after := domain.HistoryCursor{Time: from.Add(time.Minute), Source: "coingecko"}
+	rows := []domain.Coin{{Symbol: "BTC", Price: dec(102), UpdatedAt: from.Add(2 * time.Minute)}}
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().HistoryPage(gomock.Any(), "BTC", "usd", from, to, after, DefaultHistoryLimit+1).Return(rows, nil)
+
+	got, next, err := svc.HistoryPage(ctx, "BTC", "usd", from, to, after, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || !next.IsZero() {
+		t.Fatalf("expected last page, got %+v next=%+v", got, next)
+	}
+}
+
+func TestHistoryPage_InvalidArguments(t *testing.T) {
+	ctx, ctrl, _, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		from, to time.Time
+		limit    int
+	}{
+		{"reversed range", from, from.Add(-time.Hour), 10},
+		{"empty range", from, from, 10},
+		{"limit too large", from, from.Add(time.Hour), MaxHistoryLimit + 1},
+	}
+	for _, tc := range cases {
+		if _, _, err := svc.HistoryPage(ctx, "BTC", "", tc.from, tc.to, domain.HistoryCursor{}, tc.limit); !errors.Is(err, derrors.ErrInvalidArgument) {
+			t.Errorf("%s: expected ErrInvalidArgument, got %v", tc.name, err)
+		}
+	}
+}
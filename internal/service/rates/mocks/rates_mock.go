@@ -11,6 +11,7 @@ import (
 
 	domain "github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
 	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
 )
 
 // MockCryptoProvider is a mock of CryptoProvider interface.
@@ -37,18 +38,18 @@ func (m *MockCryptoProvider) EXPECT() *MockCryptoProviderMockRecorder {
 }
 
 // FetchRates mocks base method.
-func (m *MockCryptoProvider) FetchRates(ctx context.Context) ([]domain.Coin, error) {
+func (m *MockCryptoProvider) FetchRates(ctx context.Context, coins []domain.CoinInfo, currency string) ([]domain.Coin, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchRates", ctx)
+	ret := m.ctrl.Call(m, "FetchRates", ctx, coins, currency)
 	ret0, _ := ret[0].([]domain.Coin)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchRates indicates an expected call of FetchRates.
-func (mr *MockCryptoProviderMockRecorder) FetchRates(ctx interface{}) *gomock.Call {
+func (mr *MockCryptoProviderMockRecorder) FetchRates(ctx, coins, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchRates", reflect.TypeOf((*MockCryptoProvider)(nil).FetchRates), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchRates", reflect.TypeOf((*MockCryptoProvider)(nil).FetchRates), ctx, coins, currency)
 }
 
 // MockIngestion is a mock of Ingestion interface.
@@ -88,6 +89,132 @@ func (mr *MockIngestionMockRecorder) FetchAndSaveCurrency(ctx interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAndSaveCurrency", reflect.TypeOf((*MockIngestion)(nil).FetchAndSaveCurrency), ctx)
 }
 
+// MockPriceObserver is a mock of PriceObserver interface.
+type MockPriceObserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceObserverMockRecorder
+}
+
+// MockPriceObserverMockRecorder is the mock recorder for MockPriceObserver.
+type MockPriceObserverMockRecorder struct {
+	mock *MockPriceObserver
+}
+
+// NewMockPriceObserver creates a new mock instance.
+func NewMockPriceObserver(ctrl *gomock.Controller) *MockPriceObserver {
+	mock := &MockPriceObserver{ctrl: ctrl}
+	mock.recorder = &MockPriceObserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceObserver) EXPECT() *MockPriceObserverMockRecorder {
+	return m.recorder
+}
+
+// OnPrices mocks base method.
+func (m *MockPriceObserver) OnPrices(ctx context.Context, prices []domain.Coin) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnPrices", ctx, prices)
+}
+
+// OnPrices indicates an expected call of OnPrices.
+func (mr *MockPriceObserverMockRecorder) OnPrices(ctx, prices interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnPrices", reflect.TypeOf((*MockPriceObserver)(nil).OnPrices), ctx, prices)
+}
+
+// MockLatestPrices is a mock of LatestPrices interface.
+type MockLatestPrices struct {
+	ctrl     *gomock.Controller
+	recorder *MockLatestPricesMockRecorder
+}
+
+// MockLatestPricesMockRecorder is the mock recorder for MockLatestPrices.
+type MockLatestPricesMockRecorder struct {
+	mock *MockLatestPrices
+}
+
+// NewMockLatestPrices creates a new mock instance.
+func NewMockLatestPrices(ctrl *gomock.Controller) *MockLatestPrices {
+	mock := &MockLatestPrices{ctrl: ctrl}
+	mock.recorder = &MockLatestPricesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLatestPrices) EXPECT() *MockLatestPricesMockRecorder {
+	return m.recorder
+}
+
+// GetAllCoins mocks base method.
+func (m *MockLatestPrices) GetAllCoins(ctx context.Context, currency string) ([]domain.Coin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllCoins", ctx, currency)
+	ret0, _ := ret[0].([]domain.Coin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllCoins indicates an expected call of GetAllCoins.
+func (mr *MockLatestPricesMockRecorder) GetAllCoins(ctx, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCoins", reflect.TypeOf((*MockLatestPrices)(nil).GetAllCoins), ctx, currency)
+}
+
+// MockCoinRegistry is a mock of CoinRegistry interface.
+type MockCoinRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoinRegistryMockRecorder
+}
+
+// MockCoinRegistryMockRecorder is the mock recorder for MockCoinRegistry.
+type MockCoinRegistryMockRecorder struct {
+	mock *MockCoinRegistry
+}
+
+// NewMockCoinRegistry creates a new mock instance.
+func NewMockCoinRegistry(ctrl *gomock.Controller) *MockCoinRegistry {
+	mock := &MockCoinRegistry{ctrl: ctrl}
+	mock.recorder = &MockCoinRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoinRegistry) EXPECT() *MockCoinRegistryMockRecorder {
+	return m.recorder
+}
+
+// GetCoinInfo mocks base method.
+func (m *MockCoinRegistry) GetCoinInfo(ctx context.Context, symbol string) (domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoinInfo", ctx, symbol)
+	ret0, _ := ret[0].(domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoinInfo indicates an expected call of GetCoinInfo.
+func (mr *MockCoinRegistryMockRecorder) GetCoinInfo(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinInfo", reflect.TypeOf((*MockCoinRegistry)(nil).GetCoinInfo), ctx, symbol)
+}
+
+// ListCoins mocks base method.
+func (m *MockCoinRegistry) ListCoins(ctx context.Context, enabledOnly bool) ([]domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoins", ctx, enabledOnly)
+	ret0, _ := ret[0].([]domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoins indicates an expected call of ListCoins.
+func (mr *MockCoinRegistryMockRecorder) ListCoins(ctx, enabledOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoins", reflect.TypeOf((*MockCoinRegistry)(nil).ListCoins), ctx, enabledOnly)
+}
+
 // MockStorage is a mock of Storage interface.
 type MockStorage struct {
 	ctrl     *gomock.Controller
@@ -111,49 +238,125 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// Candles mocks base method.
+func (m *MockStorage) Candles(ctx context.Context, symbol, currency string, interval time.Duration, from, to time.Time) ([]domain.Candle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Candles", ctx, symbol, currency, interval, from, to)
+	ret0, _ := ret[0].([]domain.Candle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Candles indicates an expected call of Candles.
+func (mr *MockStorageMockRecorder) Candles(ctx, symbol, currency, interval, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Candles", reflect.TypeOf((*MockStorage)(nil).Candles), ctx, symbol, currency, interval, from, to)
+}
+
 // GetAllCoins mocks base method.
-func (m *MockStorage) GetAllCoins(ctx context.Context) ([]domain.Coin, error) {
+func (m *MockStorage) GetAllCoins(ctx context.Context, currency string) ([]domain.Coin, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllCoins", ctx)
+	ret := m.ctrl.Call(m, "GetAllCoins", ctx, currency)
 	ret0, _ := ret[0].([]domain.Coin)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllCoins indicates an expected call of GetAllCoins.
-func (mr *MockStorageMockRecorder) GetAllCoins(ctx interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetAllCoins(ctx, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCoins", reflect.TypeOf((*MockStorage)(nil).GetAllCoins), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCoins", reflect.TypeOf((*MockStorage)(nil).GetAllCoins), ctx, currency)
 }
 
 // GetCoinBySymbol mocks base method.
-func (m *MockStorage) GetCoinBySymbol(ctx context.Context, symbol string) (domain.Coin, error) {
+func (m *MockStorage) GetCoinBySymbol(ctx context.Context, symbol, currency string) (domain.Coin, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCoinBySymbol", ctx, symbol)
+	ret := m.ctrl.Call(m, "GetCoinBySymbol", ctx, symbol, currency)
 	ret0, _ := ret[0].(domain.Coin)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCoinBySymbol indicates an expected call of GetCoinBySymbol.
-func (mr *MockStorageMockRecorder) GetCoinBySymbol(ctx, symbol interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetCoinBySymbol(ctx, symbol, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinBySymbol", reflect.TypeOf((*MockStorage)(nil).GetCoinBySymbol), ctx, symbol, currency)
+}
+
+// GetCoinInfo mocks base method.
+func (m *MockStorage) GetCoinInfo(ctx context.Context, symbol string) (domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoinInfo", ctx, symbol)
+	ret0, _ := ret[0].(domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoinInfo indicates an expected call of GetCoinInfo.
+func (mr *MockStorageMockRecorder) GetCoinInfo(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinInfo", reflect.TypeOf((*MockStorage)(nil).GetCoinInfo), ctx, symbol)
+}
+
+// HistoryPage mocks base method.
+func (m *MockStorage) HistoryPage(ctx context.Context, symbol, currency string, from, to time.Time, after domain.HistoryCursor, limit int) ([]domain.Coin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HistoryPage", ctx, symbol, currency, from, to, after, limit)
+	ret0, _ := ret[0].([]domain.Coin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HistoryPage indicates an expected call of HistoryPage.
+func (mr *MockStorageMockRecorder) HistoryPage(ctx, symbol, currency, from, to, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HistoryPage", reflect.TypeOf((*MockStorage)(nil).HistoryPage), ctx, symbol, currency, from, to, after, limit)
+}
+
+// ListCoins mocks base method.
+func (m *MockStorage) ListCoins(ctx context.Context, enabledOnly bool) ([]domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoins", ctx, enabledOnly)
+	ret0, _ := ret[0].([]domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoins indicates an expected call of ListCoins.
+func (mr *MockStorageMockRecorder) ListCoins(ctx, enabledOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoins", reflect.TypeOf((*MockStorage)(nil).ListCoins), ctx, enabledOnly)
+}
+
+// PriceRange mocks base method.
+func (m *MockStorage) PriceRange(ctx context.Context, symbol, currency string, from, to time.Time) (decimal.Decimal, decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PriceRange", ctx, symbol, currency, from, to)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(decimal.Decimal)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PriceRange indicates an expected call of PriceRange.
+func (mr *MockStorageMockRecorder) PriceRange(ctx, symbol, currency, from, to interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinBySymbol", reflect.TypeOf((*MockStorage)(nil).GetCoinBySymbol), ctx, symbol)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PriceRange", reflect.TypeOf((*MockStorage)(nil).PriceRange), ctx, symbol, currency, from, to)
 }
 
-// History mocks base method.
-func (m *MockStorage) History(ctx context.Context, symbol string, from, to time.Time) ([]domain.Coin, error) {
+// PricesAt mocks base method.
+func (m *MockStorage) PricesAt(ctx context.Context, symbol, currency string, at []time.Time) ([]domain.Coin, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "History", ctx, symbol, from, to)
+	ret := m.ctrl.Call(m, "PricesAt", ctx, symbol, currency, at)
 	ret0, _ := ret[0].([]domain.Coin)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// History indicates an expected call of History.
-func (mr *MockStorageMockRecorder) History(ctx, symbol, from, to interface{}) *gomock.Call {
+// PricesAt indicates an expected call of PricesAt.
+func (mr *MockStorageMockRecorder) PricesAt(ctx, symbol, currency, at interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockStorage)(nil).History), ctx, symbol, from, to)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PricesAt", reflect.TypeOf((*MockStorage)(nil).PricesAt), ctx, symbol, currency, at)
 }
 
 // SaveCoins mocks base method.
@@ -193,35 +396,92 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// Candles mocks base method.
+func (m *MockService) Candles(ctx context.Context, symbol, currency, interval string, from, to time.Time) ([]domain.Candle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Candles", ctx, symbol, currency, interval, from, to)
+	ret0, _ := ret[0].([]domain.Candle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Candles indicates an expected call of Candles.
+func (mr *MockServiceMockRecorder) Candles(ctx, symbol, currency, interval, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Candles", reflect.TypeOf((*MockService)(nil).Candles), ctx, symbol, currency, interval, from, to)
+}
+
+// Currencies mocks base method.
+func (m *MockService) Currencies() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Currencies")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Currencies indicates an expected call of Currencies.
+func (mr *MockServiceMockRecorder) Currencies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Currencies", reflect.TypeOf((*MockService)(nil).Currencies))
+}
+
 // GetLatest mocks base method.
-func (m *MockService) GetLatest(ctx context.Context) ([]domain.Coin, error) {
+func (m *MockService) GetLatest(ctx context.Context, currency string) ([]domain.Coin, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatest", ctx)
+	ret := m.ctrl.Call(m, "GetLatest", ctx, currency)
 	ret0, _ := ret[0].([]domain.Coin)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLatest indicates an expected call of GetLatest.
-func (mr *MockServiceMockRecorder) GetLatest(ctx interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetLatest(ctx, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockService)(nil).GetLatest), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockService)(nil).GetLatest), ctx, currency)
 }
 
-// GetLatestBySymbol mocks base method.
-func (m *MockService) GetLatestBySymbol(ctx context.Context, symbol string, from, to time.Time) (domain.Coin, float64, float64, float64, error) {
+// GetStats mocks base method.
+func (m *MockService) GetStats(ctx context.Context, symbol, currency string, opts domain.StatsOptions) (domain.RateStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatestBySymbol", ctx, symbol, from, to)
-	ret0, _ := ret[0].(domain.Coin)
-	ret1, _ := ret[1].(float64)
-	ret2, _ := ret[2].(float64)
-	ret3, _ := ret[3].(float64)
-	ret4, _ := ret[4].(error)
-	return ret0, ret1, ret2, ret3, ret4
+	ret := m.ctrl.Call(m, "GetStats", ctx, symbol, currency, opts)
+	ret0, _ := ret[0].(domain.RateStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockServiceMockRecorder) GetStats(ctx, symbol, currency, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockService)(nil).GetStats), ctx, symbol, currency, opts)
+}
+
+// HistoryPage mocks base method.
+func (m *MockService) HistoryPage(ctx context.Context, symbol, currency string, from, to time.Time, after domain.HistoryCursor, limit int) ([]domain.Coin, domain.HistoryCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HistoryPage", ctx, symbol, currency, from, to, after, limit)
+	ret0, _ := ret[0].([]domain.Coin)
+	ret1, _ := ret[1].(domain.HistoryCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// HistoryPage indicates an expected call of HistoryPage.
+func (mr *MockServiceMockRecorder) HistoryPage(ctx, symbol, currency, from, to, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HistoryPage", reflect.TypeOf((*MockService)(nil).HistoryPage), ctx, symbol, currency, from, to, after, limit)
+}
+
+// TrackedCoins mocks base method.
+func (m *MockService) TrackedCoins(ctx context.Context) ([]domain.CoinInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrackedCoins", ctx)
+	ret0, _ := ret[0].([]domain.CoinInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// GetLatestBySymbol indicates an expected call of GetLatestBySymbol.
-func (mr *MockServiceMockRecorder) GetLatestBySymbol(ctx, symbol, from, to interface{}) *gomock.Call {
+// TrackedCoins indicates an expected call of TrackedCoins.
+func (mr *MockServiceMockRecorder) TrackedCoins(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestBySymbol", reflect.TypeOf((*MockService)(nil).GetLatestBySymbol), ctx, symbol, from, to)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackedCoins", reflect.TypeOf((*MockService)(nil).TrackedCoins), ctx)
 }
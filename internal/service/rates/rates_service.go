@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,155 +14,285 @@ import (
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
+// DefaultCurrency — валюта котировки, если список валют не задан
+const DefaultCurrency = "usd"
+
+const (
+	// DefaultStatsWindow — окно min/max по умолчанию
+	DefaultStatsWindow = 24 * time.Hour
+	// DefaultChangeWindow — окно основного изменения цены по умолчанию
+	DefaultChangeWindow = time.Hour
+	// MaxStatsWindow — предел окон статистики; за свёрнутые периоды min/max берётся из их low/high
+	MaxStatsWindow = 90 * 24 * time.Hour
+)
+
+// ChangeWindows — окна блока изменений цены (1ч/24ч/7д/30д)
+var ChangeWindows = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
 type Service struct {
 	storage        interfaces.Storage
 	cryptoProvider interfaces.CryptoProvider
+	currencies     []string // первая — валюта по умолчанию
+	observer       interfaces.PriceObserver
 	logger         *slog.Logger
 }
 
-func NewService(storage interfaces.Storage, provider interfaces.CryptoProvider, logger *slog.Logger) *Service {
+// NewService — currencies: валюты котировки (vs_currency), загружаемые на каждом цикле.
+func NewService(storage interfaces.Storage, provider interfaces.CryptoProvider, currencies []string, logger *slog.Logger) *Service {
 	return &Service{
 		storage:        storage,
 		cryptoProvider: provider,
+		currencies:     NormalizeCurrencies(currencies),
 		logger:         logger,
 	}
 }
 
-// FetchAndSaveCurrency — получает список монет, запрашивает их курсы у провайдера и сохраняет цены в БД.
+// NormalizeCurrencies — валюты котировки в нижнем регистре без пустых и повторов;
+// пустой список — DefaultCurrency. Цикл загрузки делает по одному запросу к провайдеру на валюту.
+func NormalizeCurrencies(currencies []string) []string {
+	normalized := make([]string, 0, len(currencies))
+	for _, c := range currencies {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c != "" && !slices.Contains(normalized, c) {
+			normalized = append(normalized, c)
+		}
+	}
+	if len(normalized) == 0 {
+		normalized = []string{DefaultCurrency}
+	}
+	return normalized
+}
+
+// SetPriceObserver — получатель цен, сохранённых каждым циклом FetchAndSaveCurrency (nil — отключить).
+func (s *Service) SetPriceObserver(o interfaces.PriceObserver) {
+	s.observer = o
+}
+
+// Currencies — поддерживаемые валюты котировки; первая — по умолчанию.
+func (s *Service) Currencies() []string {
+	return slices.Clone(s.currencies)
+}
+
+// resolveCurrency — нормализует валюту запроса; пустая — валюта по умолчанию.
+func (s *Service) resolveCurrency(currency string) (string, error) {
+	currency = strings.ToLower(strings.TrimSpace(currency))
+	if currency == "" {
+		return s.currencies[0], nil
+	}
+	if !slices.Contains(s.currencies, currency) {
+		return "", fmt.Errorf("%w: %s", errs.ErrUnsupportedCurrency, currency)
+	}
+	return currency, nil
+}
+
+// FetchAndSaveCurrency — берёт включённые монеты из реестра, запрашивает их курсы у провайдера
+// во всех настроенных валютах и сохраняет цены в БД.
+// Ошибка одной валюты не мешает сохранить остальные.
 func (s *Service) FetchAndSaveCurrency(ctx context.Context) error {
-	symbols, err := s.storage.GetAllCoins(ctx)
+	coins, err := s.storage.ListCoins(ctx, true)
 	if err != nil {
-		s.logger.Error("failed to get all coins from storage", "err", err)
-		return fmt.Errorf("%w: storage.GetAllCoins: %w", errs.ErrInternal, err)
+		s.logger.Error("failed to list tracked coins", "err", err)
+		return fmt.Errorf("%w: storage.ListCoins: %w", errs.ErrInternal, err)
+	}
+	if len(coins) == 0 {
+		s.logger.Warn("no tracked coins in registry, nothing to fetch")
+		return nil
 	}
 
-	rates, err := s.cryptoProvider.FetchRates(ctx)
-	if err != nil {
-		s.logger.Error("fetch rates", "err", err)
-		return fmt.Errorf("%w: provider.FetchRates: %w", errs.ErrInternal, err)
+	var (
+		items    []domain.Coin
+		failures []error
+	)
+	for _, currency := range s.currencies {
+		rates, err := s.cryptoProvider.FetchRates(ctx, coins, currency)
+		if err != nil {
+			s.logger.Error("fetch rates", "currency", currency, "err", err)
+			failures = append(failures, fmt.Errorf("%s: %w", currency, err))
+			continue
+		}
+		items = append(items, s.matchRates(coins, rates, currency)...)
+	}
+
+	if len(items) > 0 {
+		if err := s.storage.SaveCoins(ctx, items); err != nil {
+			s.logger.Error("save prices to db failed", "count", len(items), "err", err)
+			return fmt.Errorf("%w: storage.SaveCoins(count=%d): %w", errs.ErrInternal, len(items), err)
+		}
+		s.logger.Info("rates saved", "count", len(items), "currencies", len(s.currencies))
+		if s.observer != nil {
+			s.observer.OnPrices(ctx, items)
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: provider.FetchRates: %w", errs.ErrInternal, errors.Join(failures...))
 	}
+	return nil
+}
 
+// matchRates — сопоставляет ответ провайдера с монетами реестра по символу
+func (s *Service) matchRates(coins []domain.CoinInfo, rates []domain.Coin, currency string) []domain.Coin {
 	// создаём map для быстрого поиска цены по символу
 	rateMap := make(map[string]domain.Coin)
 	for _, r := range rates {
 		rateMap[strings.ToUpper(r.Symbol)] = r
 	}
 
-	items := make([]domain.Coin, 0, len(symbols))
+	items := make([]domain.Coin, 0, len(coins))
 	now := utils.NowFunc()
-	for _, sym := range symbols {
-		u := strings.ToUpper(sym.Symbol)
+	for _, coin := range coins {
+		u := strings.ToUpper(coin.Symbol)
 		r, ok := rateMap[u]
 		if !ok {
-			s.logger.Warn("missing rate for coin", "symbol", u)
+			s.logger.Warn("missing rate for coin", "symbol", u, "currency", currency, "provider_id", coin.ProviderID)
 			continue
 		}
 		r.Symbol = u
+		r.Currency = currency
 		if r.UpdatedAt.IsZero() {
 			r.UpdatedAt = now
 		}
 		items = append(items, r)
 	}
+	return items
+}
 
-	if err := s.storage.SaveCoins(ctx, items); err != nil {
-		s.logger.Error("save prices to db failed", "count", len(items), "err", err)
-		return fmt.Errorf("%w: storage.SaveCoins(count=%d): %w", errs.ErrInternal, len(items), err)
+// TrackedCoins — список включённых монет реестра.
+func (s *Service) TrackedCoins(ctx context.Context) ([]domain.CoinInfo, error) {
+	coins, err := s.storage.ListCoins(ctx, true)
+	if err != nil {
+		s.logger.Error("failed to list tracked coins", "err", err)
+		return nil, fmt.Errorf("%w: storage.ListCoins: %w", errs.ErrInternal, err)
 	}
-	s.logger.Info("rates saved", "count", len(items))
-
-	return nil
+	return coins, nil
 }
 
-func (s *Service) GetLatest(ctx context.Context) ([]domain.Coin, error) {
-	items, err := s.storage.GetAllCoins(ctx)
+func (s *Service) GetLatest(ctx context.Context, currency string) ([]domain.Coin, error) {
+	currency, err := s.resolveCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.storage.GetAllCoins(ctx, currency)
 	if err != nil {
-		s.logger.Error("failed to get all coins", "err", err)
-		return nil, fmt.Errorf("%w: storage.GetAllCoins: %w", errs.ErrInternal, err)
+		s.logger.Error("failed to get all coins", "currency", currency, "err", err)
+		return nil, fmt.Errorf("%w: storage.GetAllCoins(%s): %w", errs.ErrInternal, currency, err)
 	}
 	if len(items) == 0 {
-		s.logger.Warn("no latest coins available")
+		s.logger.Warn("no latest coins available", "currency", currency)
 		return nil, errs.ErrPriceNotFound
 	}
 	s.logger.Info("loaded latest coins", "count", len(items))
 	return items, nil
 }
 
-func (s *Service) GetLatestBySymbol(ctx context.Context, symbol string, from, to time.Time) (latest domain.Coin, min float64, max float64, pct float64, err error) {
-	// Нормализуем символ
+// GetStats — текущая цена монеты, min/max за окно opts.Window, изменение за opts.Change
+// и блок изменений за ChangeWindows. Все окна отсчитываются от текущего момента.
+func (s *Service) GetStats(ctx context.Context, symbol, currency string, opts domain.StatsOptions) (domain.RateStats, error) {
+	// Нормализуем символ, валюту и окна
 	symbol = strings.ToUpper(symbol)
-
-	// Нормализуем окно времени: если не задано — последние 24 часа; всегда UTC
-	if to.IsZero() {
-		to = utils.NowFunc()
-	} else {
-		to = to.UTC()
+	currency, err := s.resolveCurrency(currency)
+	if err != nil {
+		return domain.RateStats{}, err
+	}
+	if opts.Window == 0 {
+		opts.Window = DefaultStatsWindow
 	}
-	if from.IsZero() {
-		from = to.Add(-24 * time.Hour)
-	} else {
-		from = from.UTC()
+	if opts.Change == 0 {
+		opts.Change = DefaultChangeWindow
+	}
+	for _, w := range []time.Duration{opts.Window, opts.Change} {
+		if w < time.Minute || w > MaxStatsWindow {
+			return domain.RateStats{}, fmt.Errorf("%w: window %s out of range [1m, %s]",
+				errs.ErrInvalidArgument, utils.FormatWindow(w), utils.FormatWindow(MaxStatsWindow))
+		}
+	}
+	to := utils.NowFunc()
+	from := to.Add(-opts.Window)
+
+	// Монета должна быть в реестре и включена
+	if err := s.checkTracked(ctx, symbol); err != nil {
+		return domain.RateStats{}, err
 	}
 
-	// Текущая цена на момент `to`
-	latest, err = s.storage.GetCoinBySymbol(ctx, symbol)
+	// Текущая цена
+	latest, err := s.storage.GetCoinBySymbol(ctx, symbol, currency)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			s.logger.Warn("coin not found", "symbol", symbol)
-			return domain.Coin{}, 0, 0, 0, errs.ErrCoinNotFound
+			s.logger.Warn("no prices for coin yet", "symbol", symbol, "currency", currency)
+			return domain.RateStats{}, errs.ErrPriceNotFound
 		}
 		s.logger.Error("failed to get coin by symbol", "symbol", symbol, "err", err)
-		return domain.Coin{}, 0, 0, 0, fmt.Errorf("%w: storage.GetCoinBySymbol(%s): %w", errs.ErrInternal, symbol, err)
+		return domain.RateStats{}, fmt.Errorf("%w: storage.GetCoinBySymbol(%s): %w", errs.ErrInternal, symbol, err)
+	}
+
+	// Мин/макс в окне [from..to]
+	s.logger.Debug("loading price range", "symbol", symbol, "currency", currency, "from", from, "to", to)
+	min, max, err := s.storage.PriceRange(ctx, symbol, currency, from, to)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.RateStats{}, errs.ErrPriceNotFound
+		}
+		return domain.RateStats{}, fmt.Errorf("%w: storage.PriceRange(%s): %w", errs.ErrInternal, symbol, err)
 	}
 
-	// История в окне [from..to]
-	s.logger.Debug("loading history window", "symbol", symbol, "from", from, "to", to)
-	rows, err := s.storage.History(ctx, symbol, from, to)
+	// Цены на начало окон изменений — основного и стандартных — одним запросом
+	windows := append([]time.Duration{opts.Change}, ChangeWindows...)
+	at := make([]time.Time, len(windows))
+	for i, w := range windows {
+		at[i] = to.Add(-w)
+	}
+	base, err := s.storage.PricesAt(ctx, symbol, currency, at)
 	if err != nil {
-		return domain.Coin{}, 0, 0, 0, fmt.Errorf("%w: storage.History(%s): %w", errs.ErrInternal, symbol, err)
+		return domain.RateStats{}, fmt.Errorf("%w: storage.PricesAt(%s): %w", errs.ErrInternal, symbol, err)
+	}
+	changes := make([]domain.Change, len(windows))
+	for i, w := range windows {
+		changes[i] = domain.Change{Window: w, Pct: changePct(latest.Price, base[i].Price)}
 	}
-	if len(rows) == 0 {
-		return domain.Coin{}, 0, 0, 0, errs.ErrPriceNotFound
+	if changes[0].Pct == nil {
+		// Не роняем ответ: изменения без цены на начало окна отдаются пустыми
+		s.logger.Warn("insufficient data for pct", "symbol", symbol, "threshold", at[0])
 	}
 
-	// Мин/Макс за окно
-	min, max = rows[0].Price, rows[0].Price
-	for i := 1; i < len(rows); i++ {
-		p := rows[i].Price
-		if p < min {
-			min = p
-		}
-		if p > max {
-			max = p
-		}
+	s.logger.Info("computed stats", "symbol", symbol, "window", opts.Window, "min", min.String(), "max", max.String())
+	return domain.RateStats{
+		Latest:  latest,
+		Window:  opts.Window,
+		Min:     min,
+		Max:     max,
+		Change:  changes[0],
+		Changes: changes[1:],
+	}, nil
+}
+
+// changePct — изменение latest относительно base в процентах; nil — цены на начало окна нет.
+// Разность и отношение считаются точно, во float переводится только итоговый процент.
+func changePct(latest, base decimal.Decimal) *float64 {
+	if base.IsZero() {
+		return nil
 	}
+	pct := latest.Sub(base).Div(base).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	return &pct
+}
 
-	// Процент за последний час на момент `to` (ищем самую позднюю точку <= threshold)
-	threshold := to.Add(-1 * time.Hour)
-	var (
-		prevPrice float64
-		prevFound bool
-		prevAt    time.Time
-	)
-	for i := 0; i < len(rows); i++ {
-		ts := rows[i].UpdatedAt
-		if !ts.After(threshold) {
-			if !prevFound || ts.After(prevAt) || ts.Equal(prevAt) {
-				prevPrice = rows[i].Price
-				prevAt = ts
-				prevFound = true
-			}
+// checkTracked — монета есть в реестре и включена, иначе errs.ErrCoinNotFound
+func (s *Service) checkTracked(ctx context.Context, symbol string) error {
+	info, err := s.storage.GetCoinInfo(ctx, symbol)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("coin not found", "symbol", symbol)
+			return errs.ErrCoinNotFound
 		}
+		s.logger.Error("failed to get coin info", "symbol", symbol, "err", err)
+		return fmt.Errorf("%w: storage.GetCoinInfo(%s): %w", errs.ErrInternal, symbol, err)
 	}
-	if !prevFound || prevPrice == 0 {
-		s.logger.Warn("insufficient data for pct", "symbol", symbol, "threshold", threshold)
-		// Не роняем ответ: возвращаем latest/min/max, а pct=0 (транспорт может отдать null)
-		pct = 0
-	} else {
-		pct = ((latest.Price - prevPrice) / prevPrice) * 100
+	if !info.Enabled {
+		s.logger.Warn("coin disabled", "symbol", symbol)
+		return errs.ErrCoinNotFound
 	}
-
-	s.logger.Info("computed stats", "symbol", symbol, "min", min, "max", max, "pct", pct)
-	return latest, min, max, pct, nil
+	return nil
 }
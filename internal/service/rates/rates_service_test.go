@@ -10,11 +10,22 @@ import (
 
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
 	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/infra/providers"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
 	ratesmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/rates/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
+var (
+	btcInfo = domain.CoinInfo{ProviderID: "bitcoin", Symbol: "BTC", Name: "Bitcoin", Enabled: true}
+	ethInfo = domain.CoinInfo{ProviderID: "ethereum", Symbol: "ETH", Name: "Ethereum", Enabled: true}
+)
+
+// dec — точная цена для фикстур
+func dec(v int64) decimal.Decimal { return decimal.NewFromInt(v) }
+
 // helper to build service with mocks
 func setupSvc(t *testing.T) (context.Context, *gomock.Controller, *ratesmocks.MockStorage, *ratesmocks.MockCryptoProvider, *Service) {
 	t.Helper()
@@ -22,7 +33,7 @@ func setupSvc(t *testing.T) (context.Context, *gomock.Controller, *ratesmocks.Mo
 	ctrl := gomock.NewController(t)
 	storage := ratesmocks.NewMockStorage(ctrl)
 	provider := ratesmocks.NewMockCryptoProvider(ctrl)
-	svc := NewService(storage, provider, slog.Default())
+	svc := NewService(storage, provider, []string{"usd"}, slog.Default())
 	return ctx, ctrl, storage, provider, svc
 }
 
@@ -36,12 +47,12 @@ func TestGetLatest_Success(t *testing.T) {
 
 	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
 	in := []domain.Coin{
-		{Symbol: "BTC", Price: 100.0, UpdatedAt: now},
-		{Symbol: "ETH", Price: 200.0, UpdatedAt: now},
+		{Symbol: "BTC", Price: dec(100), UpdatedAt: now},
+		{Symbol: "ETH", Price: dec(200), UpdatedAt: now},
 	}
-	storage.EXPECT().GetAllCoins(gomock.Any()).Return(in, nil)
+	storage.EXPECT().GetAllCoins(gomock.Any(), "usd").Return(in, nil)
 
-	got, err := svc.GetLatest(ctx)
+	got, err := svc.GetLatest(ctx, "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -57,142 +68,221 @@ func TestGetLatest_RepoError(t *testing.T) {
 	ctx, ctrl, storage, _, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	storage.EXPECT().GetAllCoins(gomock.Any()).Return(nil, errors.New("db down"))
+	storage.EXPECT().GetAllCoins(gomock.Any(), "usd").Return(nil, errors.New("db down"))
 
-	_, err := svc.GetLatest(ctx)
+	_, err := svc.GetLatest(ctx, "")
 	if err == nil || !errors.Is(err, derrors.ErrInternal) {
 		t.Fatalf("expected ErrInternal, got %v", err)
 	}
 }
 
+func TestGetLatest_UnsupportedCurrency(t *testing.T) {
+	ctx, ctrl, _, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	_, err := svc.GetLatest(ctx, "jpy")
+	if !errors.Is(err, derrors.ErrUnsupportedCurrency) {
+		t.Fatalf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+}
+
 func TestGetLatest_Empty(t *testing.T) {
 	ctx, ctrl, storage, _, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	storage.EXPECT().GetAllCoins(gomock.Any()).Return([]domain.Coin{}, nil)
+	storage.EXPECT().GetAllCoins(gomock.Any(), "usd").Return([]domain.Coin{}, nil)
 
-	_, err := svc.GetLatest(ctx)
+	_, err := svc.GetLatest(ctx, "")
 	if err == nil || !errors.Is(err, derrors.ErrPriceNotFound) {
 		t.Fatalf("expected ErrPriceNotFound, got %v", err)
 	}
 }
 
 // -------------------------
-// GetLatestBySymbol
+// GetStats
 // -------------------------
 
-func TestGetLatestBySymbol_CoinNotFound(t *testing.T) {
+// freezeNow — подменяет utils.NowFunc до конца теста
+func freezeNow(t *testing.T, now time.Time) {
+	t.Helper()
+	prev := utils.NowFunc
+	utils.NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { utils.NowFunc = prev })
+}
+
+// changePoints — моменты начала окон изменений: основное окно, затем ChangeWindows
+func changePoints(to time.Time, change time.Duration) []time.Time {
+	at := []time.Time{to.Add(-change)}
+	for _, w := range ChangeWindows {
+		at = append(at, to.Add(-w))
+	}
+	return at
+}
+
+func TestGetStats_CoinNotFound(t *testing.T) {
 	ctx, ctrl, storage, _, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	from := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
-	to := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(domain.CoinInfo{}, pgx.ErrNoRows)
 
-	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC").Return(domain.Coin{}, pgx.ErrNoRows)
+	_, err := svc.GetStats(ctx, "BTC", "", domain.StatsOptions{})
+	if err == nil || !errors.Is(err, derrors.ErrCoinNotFound) {
+		t.Fatalf("expected ErrCoinNotFound, got %v", err)
+	}
+}
+
+func TestGetStats_CoinDisabled(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
 
-	_, _, _, _, err := svc.GetLatestBySymbol(ctx, "BTC", from, to)
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(domain.CoinInfo{ProviderID: "bitcoin", Symbol: "BTC", Enabled: false}, nil)
+
+	_, err := svc.GetStats(ctx, "BTC", "", domain.StatsOptions{})
 	if err == nil || !errors.Is(err, derrors.ErrCoinNotFound) {
 		t.Fatalf("expected ErrCoinNotFound, got %v", err)
 	}
 }
 
-func TestGetLatestBySymbol_NoHistory(t *testing.T) {
+func TestGetStats_NoPricesYet(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC", "usd").Return(domain.Coin{}, pgx.ErrNoRows)
+
+	_, err := svc.GetStats(ctx, "BTC", "", domain.StatsOptions{})
+	if err == nil || !errors.Is(err, derrors.ErrPriceNotFound) {
+		t.Fatalf("expected ErrPriceNotFound, got %v", err)
+	}
+}
+
+func TestGetStats_NoHistory(t *testing.T) {
 	ctx, ctrl, storage, _, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	from := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	freezeNow(t, to)
 
-	latest := domain.Coin{Symbol: "BTC", Price: 105, UpdatedAt: to}
-	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC").Return(latest, nil)
-	storage.EXPECT().History(gomock.Any(), "BTC", from, to).Return([]domain.Coin{}, nil)
+	latest := domain.Coin{Symbol: "BTC", Price: dec(105), UpdatedAt: to}
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC", "usd").Return(latest, nil)
+	storage.EXPECT().PriceRange(gomock.Any(), "BTC", "usd", to.Add(-24*time.Hour), to).Return(decimal.Decimal{}, decimal.Decimal{}, pgx.ErrNoRows)
 
-	_, _, _, _, err := svc.GetLatestBySymbol(ctx, "BTC", from, to)
+	_, err := svc.GetStats(ctx, "BTC", "", domain.StatsOptions{})
 	if err == nil || !errors.Is(err, derrors.ErrPriceNotFound) {
 		t.Fatalf("expected ErrPriceNotFound, got %v", err)
 	}
 }
 
-func TestGetLatestBySymbol_InternalHistoryError(t *testing.T) {
+func TestGetStats_InternalHistoryError(t *testing.T) {
 	ctx, ctrl, storage, _, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	from := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	freezeNow(t, to)
 
-	latest := domain.Coin{Symbol: "BTC", Price: 105, UpdatedAt: to}
-	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC").Return(latest, nil)
-	storage.EXPECT().History(gomock.Any(), "BTC", from, to).Return(nil, errors.New("db error"))
+	latest := domain.Coin{Symbol: "BTC", Price: dec(105), UpdatedAt: to}
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC", "usd").Return(latest, nil)
+	storage.EXPECT().PriceRange(gomock.Any(), "BTC", "usd", to.Add(-24*time.Hour), to).Return(decimal.Decimal{}, decimal.Decimal{}, errors.New("db error"))
 
-	_, _, _, _, err := svc.GetLatestBySymbol(ctx, "BTC", from, to)
+	_, err := svc.GetStats(ctx, "BTC", "", domain.StatsOptions{})
 	if err == nil || !errors.Is(err, derrors.ErrInternal) {
 		t.Fatalf("expected ErrInternal, got %v", err)
 	}
 }
 
-func TestGetLatestBySymbol_NoPrevForPct(t *testing.T) {
+func TestGetStats_InvalidWindow(t *testing.T) {
+	ctx, ctrl, _, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	for _, opts := range []domain.StatsOptions{
+		{Window: 30 * time.Second},
+		{Change: 365 * 24 * time.Hour},
+	} {
+		if _, err := svc.GetStats(ctx, "BTC", "", opts); !errors.Is(err, derrors.ErrInvalidArgument) {
+			t.Errorf("%+v: expected ErrInvalidArgument, got %v", opts, err)
+		}
+	}
+}
+
+func TestGetStats_NoPrevForPct(t *testing.T) {
 	ctx, ctrl, storage, _, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	from := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	freezeNow(t, to)
 
-	latest := domain.Coin{Symbol: "BTC", Price: 105, UpdatedAt: to}
-	history := []domain.Coin{
-		{Symbol: "BTC", Price: 90, UpdatedAt: to.Add(-45 * time.Minute)},
-		{Symbol: "BTC", Price: 110, UpdatedAt: to.Add(-30 * time.Minute)},
-	}
+	latest := domain.Coin{Symbol: "BTC", Price: dec(105), UpdatedAt: to}
 
-	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC").Return(latest, nil)
-	storage.EXPECT().History(gomock.Any(), "BTC", from, to).Return(history, nil)
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC", "usd").Return(latest, nil)
+	storage.EXPECT().PriceRange(gomock.Any(), "BTC", "usd", to.Add(-24*time.Hour), to).Return(dec(90), dec(110), nil)
+	// цен раньше порогов нет ни для одного окна
+	storage.EXPECT().PricesAt(gomock.Any(), "BTC", "usd", changePoints(to, time.Hour)).Return(make([]domain.Coin, 5), nil)
 
-	gotLatest, minPrice, maxPrice, pct, err := svc.GetLatestBySymbol(ctx, "BTC", from, to)
+	got, err := svc.GetStats(ctx, "BTC", "", domain.StatsOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if gotLatest.Symbol != "BTC" || gotLatest.Price != 105 || !gotLatest.UpdatedAt.Equal(to) {
-		t.Fatalf("unexpected latest: %+v", gotLatest)
+	if got.Latest.Symbol != "BTC" || !got.Latest.Price.Equal(dec(105)) || !got.Latest.UpdatedAt.Equal(to) {
+		t.Fatalf("unexpected latest: %+v", got.Latest)
+	}
+	if !got.Min.Equal(dec(90)) || !got.Max.Equal(dec(110)) {
+		t.Fatalf("unexpected min/max: (%v, %v)", got.Min, got.Max)
 	}
-	if minPrice != 90 || maxPrice != 110 {
-		t.Fatalf("unexpected min/max: (%v, %v)", minPrice, maxPrice)
+	if got.Change.Pct != nil {
+		t.Fatalf("expected no pct when there is no prev <= threshold, got %v", *got.Change.Pct)
 	}
-	// expected pct == 0 when there is no prev <= threshold
-	if pct != 0 {
-		t.Fatalf("expected pct == 0 when no prev <= threshold, got %v", pct)
+	for _, ch := range got.Changes {
+		if ch.Pct != nil {
+			t.Fatalf("expected no pct for %s, got %v", ch.Window, *ch.Pct)
+		}
 	}
 }
 
-func TestGetLatestBySymbol_Success(t *testing.T) {
+func TestGetStats_Success(t *testing.T) {
 	ctx, ctrl, storage, _, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	from := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
-
-	latest := domain.Coin{Symbol: "BTC", Price: 105, UpdatedAt: to}
-	// history covers min=90, max=110, and prev (<= to-1h) = 100
-	history := []domain.Coin{
-		{Symbol: "BTC", Price: 90, UpdatedAt: from.Add(1 * time.Hour)},    // min
-		{Symbol: "BTC", Price: 100, UpdatedAt: to.Add(-1 * time.Hour)},    // prev (threshold)
-		{Symbol: "BTC", Price: 110, UpdatedAt: to.Add(-30 * time.Minute)}, // max
+	freezeNow(t, to)
+
+	latest := domain.Coin{Symbol: "BTC", Price: dec(105), UpdatedAt: to}
+	// цены на начало окон: 24ч (основное), 1ч, 24ч, 7д, 30д (нет данных)
+	base := []domain.Coin{
+		{Price: dec(100)},
+		{Price: dec(100)},
+		{Price: dec(100)},
+		{Price: dec(84)},
+		{},
 	}
 
-	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC").Return(latest, nil)
-	storage.EXPECT().History(gomock.Any(), "BTC", from, to).Return(history, nil)
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "BTC").Return(btcInfo, nil)
+	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "BTC", "usd").Return(latest, nil)
+	storage.EXPECT().PriceRange(gomock.Any(), "BTC", "usd", to.Add(-7*24*time.Hour), to).Return(dec(90), dec(110), nil)
+	storage.EXPECT().PricesAt(gomock.Any(), "BTC", "usd", changePoints(to, 24*time.Hour)).Return(base, nil)
 
-	gotLatest, minPrice, maxPrice, pct, err := svc.GetLatestBySymbol(ctx, "BTC", from, to)
+	got, err := svc.GetStats(ctx, "BTC", "", domain.StatsOptions{Window: 7 * 24 * time.Hour, Change: 24 * time.Hour})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if gotLatest.Symbol != "BTC" || gotLatest.Price != 105 || !gotLatest.UpdatedAt.Equal(to) {
-		t.Fatalf("unexpected latest: %+v", gotLatest)
-	}
-	if minPrice != 90 || maxPrice != 110 {
-		t.Fatalf("unexpected min/max: (%v, %v)", minPrice, maxPrice)
+	if got.Window != 7*24*time.Hour || !got.Min.Equal(dec(90)) || !got.Max.Equal(dec(110)) {
+		t.Fatalf("unexpected window stats: %s (%v, %v)", got.Window, got.Min, got.Max)
 	}
 	// expected pct = (105-100)/100*100 = 5
-	if diff := pct - 5.0; diff < -1e-9 || diff > 1e-9 {
-		t.Fatalf("unexpected pct: got %v want %v", pct, 5.0)
+	if got.Change.Window != 24*time.Hour || got.Change.Pct == nil || *got.Change.Pct != 5 {
+		t.Fatalf("unexpected change: %+v", got.Change)
+	}
+	if len(got.Changes) != len(ChangeWindows) {
+		t.Fatalf("unexpected changes block: %+v", got.Changes)
+	}
+	// (105-84)/84*100 = 25
+	if p := got.Changes[2].Pct; got.Changes[2].Window != 7*24*time.Hour || p == nil || *p != 25 {
+		t.Fatalf("unexpected 7d change: %+v", got.Changes[2])
+	}
+	if got.Changes[3].Pct != nil {
+		t.Fatalf("expected no 30d change, got %v", *got.Changes[3].Pct)
 	}
 }
 
@@ -204,12 +294,10 @@ func TestFetchAndSaveCurrency_ProviderError(t *testing.T) {
 	ctx, ctrl, storage, provider, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	// теперь сервис сначала читает список символов из стораджа
-	storage.EXPECT().GetAllCoins(gomock.Any()).Return([]domain.Coin{
-		{Symbol: "BTC"},
-	}, nil)
+	// сервис сначала читает реестр включённых монет
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
 
-	provider.EXPECT().FetchRates(gomock.Any()).Return(nil, errors.New("provider down"))
+	provider.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btcInfo}, "usd").Return(nil, errors.New("provider down"))
 
 	err := svc.FetchAndSaveCurrency(ctx)
 	if err == nil || !errors.Is(err, derrors.ErrInternal) {
@@ -221,20 +309,18 @@ func TestFetchAndSaveCurrency_SaveError(t *testing.T) {
 	ctx, ctrl, storage, provider, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	// список символов, по которым нужно сохранить цены
-	storage.EXPECT().GetAllCoins(gomock.Any()).Return([]domain.Coin{
-		{Symbol: "BTC"},
-	}, nil)
+	// реестр монет, по которым нужно сохранить цены
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
 
 	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
 	itemsFromProvider := []domain.Coin{
-		{Symbol: "BTC", Price: 100, UpdatedAt: now}, // UpdatedAt не нулевой — сервис не будет его затирать
+		{Symbol: "BTC", Price: dec(100), UpdatedAt: now}, // UpdatedAt не нулевой — сервис не будет его затирать
 	}
-	provider.EXPECT().FetchRates(gomock.Any()).Return(itemsFromProvider, nil)
+	provider.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btcInfo}, "usd").Return(itemsFromProvider, nil)
 
-	// Ожидаем, что сервис передаст дальше только те элементы, что есть в symbols (BTC)
+	// Ожидаем, что сервис передаст дальше только те элементы, что есть в реестре (BTC)
 	storage.EXPECT().SaveCoins(gomock.Any(), []domain.Coin{
-		{Symbol: "BTC", Price: 100, UpdatedAt: now},
+		{Symbol: "BTC", Price: dec(100), Currency: "usd", UpdatedAt: now},
 	}).Return(errors.New("db write failed"))
 
 	err := svc.FetchAndSaveCurrency(ctx)
@@ -247,26 +333,177 @@ func TestFetchAndSaveCurrency_Success(t *testing.T) {
 	ctx, ctrl, storage, provider, svc := setupSvc(t)
 	defer ctrl.Finish()
 
-	// сервис сначала получит список поддерживаемых символов
-	storage.EXPECT().GetAllCoins(gomock.Any()).Return([]domain.Coin{
-		{Symbol: "BTC"},
-		{Symbol: "ETH"},
-	}, nil)
+	// сервис сначала получит список включённых монет реестра
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo, ethInfo}, nil)
 
 	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
 	itemsFromProvider := []domain.Coin{
-		{Symbol: "BTC", Price: 100, UpdatedAt: now},
-		{Symbol: "ETH", Price: 200, UpdatedAt: now},
+		{Symbol: "BTC", Price: dec(100), UpdatedAt: now},
+		{Symbol: "ETH", Price: dec(200), UpdatedAt: now},
 	}
-	provider.EXPECT().FetchRates(gomock.Any()).Return(itemsFromProvider, nil)
+	provider.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btcInfo, ethInfo}, "usd").Return(itemsFromProvider, nil)
 
 	// ожидаем, что в SaveCoins уйдут обе монеты в верхнем регистре символов
 	storage.EXPECT().SaveCoins(gomock.Any(), []domain.Coin{
-		{Symbol: "BTC", Price: 100, UpdatedAt: now},
-		{Symbol: "ETH", Price: 200, UpdatedAt: now},
+		{Symbol: "BTC", Price: dec(100), Currency: "usd", UpdatedAt: now},
+		{Symbol: "ETH", Price: dec(200), Currency: "usd", UpdatedAt: now},
 	}).Return(nil)
 
 	if err := svc.FetchAndSaveCurrency(ctx); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestFetchAndSaveCurrency_NotifiesObserver(t *testing.T) {
+	ctx, ctrl, storage, provider, svc := setupSvc(t)
+	defer ctrl.Finish()
+	observer := ratesmocks.NewMockPriceObserver(ctrl)
+	svc.SetPriceObserver(observer)
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	saved := []domain.Coin{{Symbol: "BTC", Price: dec(100), Currency: "usd", UpdatedAt: now}}
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
+	provider.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btcInfo}, "usd").
+		Return([]domain.Coin{{Symbol: "BTC", Price: dec(100), UpdatedAt: now}}, nil)
+	// наблюдатель получает цены только после успешного сохранения
+	gomock.InOrder(
+		storage.EXPECT().SaveCoins(gomock.Any(), saved).Return(nil),
+		observer.EXPECT().OnPrices(gomock.Any(), saved),
+	)
+
+	if err := svc.FetchAndSaveCurrency(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFetchAndSaveCurrency_MultipleCurrencies(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := ratesmocks.NewMockStorage(ctrl)
+	provider := ratesmocks.NewMockCryptoProvider(ctrl)
+	svc := NewService(storage, provider, []string{"USD", "eur", "rub"}, slog.Default())
+
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	provider.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btcInfo}, "usd").
+		Return([]domain.Coin{{Symbol: "BTC", Price: dec(100), UpdatedAt: now}}, nil)
+	provider.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btcInfo}, "eur").
+		Return([]domain.Coin{{Symbol: "BTC", Price: dec(90), UpdatedAt: now}}, nil)
+	provider.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btcInfo}, "rub").
+		Return(nil, errors.New("provider down"))
+
+	// сбой одной валюты не мешает сохранить остальные
+	storage.EXPECT().SaveCoins(gomock.Any(), []domain.Coin{
+		{Symbol: "BTC", Price: dec(100), Currency: "usd", UpdatedAt: now},
+		{Symbol: "BTC", Price: dec(90), Currency: "eur", UpdatedAt: now},
+	}).Return(nil)
+
+	err := svc.FetchAndSaveCurrency(ctx)
+	if err == nil || !errors.Is(err, derrors.ErrInternal) {
+		t.Fatalf("expected ErrInternal for failed currency, got %v", err)
+	}
+	if got := svc.Currencies(); len(got) != 3 || got[0] != "usd" {
+		t.Fatalf("unexpected currencies: %v", got)
+	}
+}
+
+func TestFetchAndSaveCurrency_EmptyRegistry(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	// пустой реестр — провайдер не вызывается, в БД ничего не пишется
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return(nil, nil)
+
+	if err := svc.FetchAndSaveCurrency(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetStats_SubCentPrices(t *testing.T) {
+	ctx, ctrl, storage, _, svc := setupSvc(t)
+	defer ctrl.Finish()
+
+	to := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	freezeNow(t, to)
+	shib := domain.CoinInfo{ProviderID: "shiba-inu", Symbol: "SHIB", Enabled: true}
+
+	// цены токена дешевле цента: во float64 min/max и процент накапливали бы ошибку
+	latest := domain.Coin{Symbol: "SHIB", Price: decimal.RequireFromString("0.00001236"), UpdatedAt: to}
+	prev := domain.Coin{Symbol: "SHIB", Price: decimal.RequireFromString("0.00001199"), UpdatedAt: to.Add(-75 * time.Minute)}
+
+	storage.EXPECT().GetCoinInfo(gomock.Any(), "SHIB").Return(shib, nil)
+	storage.EXPECT().GetCoinBySymbol(gomock.Any(), "SHIB", "usd").Return(latest, nil)
+	storage.EXPECT().PriceRange(gomock.Any(), "SHIB", "usd", to.Add(-24*time.Hour), to).
+		Return(decimal.RequireFromString("0.00001199"), decimal.RequireFromString("0.0000124"), nil)
+	storage.EXPECT().PricesAt(gomock.Any(), "SHIB", "usd", changePoints(to, time.Hour)).
+		Return([]domain.Coin{prev, prev, {}, {}, {}}, nil)
+
+	got, err := svc.GetStats(ctx, "shib", "", domain.StatsOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Min.String() != "0.00001199" || got.Max.String() != "0.0000124" {
+		t.Fatalf("unexpected min/max: (%s, %s)", got.Min, got.Max)
+	}
+	// (0.00001236-0.00001199)/0.00001199*100
+	if got.Change.Pct == nil {
+		t.Fatalf("expected pct")
+	}
+	if diff := *got.Change.Pct - 3.0859049207673; diff < -1e-9 || diff > 1e-9 {
+		t.Fatalf("unexpected pct: %v", *got.Change.Pct)
+	}
+}
+
+func TestFetchAndSaveCurrency_FullCycleWithinRateLimit(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := ratesmocks.NewMockStorage(ctrl)
+	provider := ratesmocks.NewMockCryptoProvider(ctrl)
+	currencies := []string{"usd", "eur", "rub", "btc"}
+
+	// лимит CoinGecko из config.yaml: 10 в минуту, ожидание токена не дольше 2s — цикл укладывается только в корзину
+	limited := providers.NewRateLimited(slog.Default(), "coingecko", provider, 10, len(currencies), 2*time.Second)
+	svc := NewService(storage, limited, currencies, slog.Default())
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
+	for _, c := range currencies {
+		provider.EXPECT().FetchRates(gomock.Any(), []domain.CoinInfo{btcInfo}, c).
+			Return([]domain.Coin{{Symbol: "BTC", Price: dec(100), UpdatedAt: now}}, nil)
+	}
+	storage.EXPECT().SaveCoins(gomock.Any(), gomock.Len(len(currencies))).Return(nil)
+
+	if err := svc.FetchAndSaveCurrency(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFetchAndSaveCurrency_BurstSmallerThanCycle(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := ratesmocks.NewMockStorage(ctrl)
+	provider := ratesmocks.NewMockCryptoProvider(ctrl)
+	currencies := []string{"usd", "eur", "rub", "btc"}
+
+	// корзина меньше числа валют: токен для rub и btc освободится через 6s, дольше max_wait — отказ.
+	// Такую конфигурацию отклоняет providers.CheckBudget на старте.
+	limited := providers.NewRateLimited(slog.Default(), "coingecko", provider, 10, 2, 2*time.Second)
+	svc := NewService(storage, limited, currencies, slog.Default())
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	storage.EXPECT().ListCoins(gomock.Any(), true).Return([]domain.CoinInfo{btcInfo}, nil)
+	provider.EXPECT().FetchRates(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		Return([]domain.Coin{{Symbol: "BTC", Price: dec(100), UpdatedAt: now}}, nil)
+	storage.EXPECT().SaveCoins(gomock.Any(), gomock.Len(2)).Return(nil)
+
+	if err := svc.FetchAndSaveCurrency(ctx); !errors.Is(err, derrors.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited for currencies over burst, got %v", err)
+	}
+	if err := providers.CheckBudget("coingecko", 10, 2, len(currencies), 5*time.Minute); err == nil {
+		t.Fatalf("expected startup budget check to reject burst 2 for %d currencies", len(currencies))
+	}
+}
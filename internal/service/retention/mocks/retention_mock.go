@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/retention.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRetention is a mock of Retention interface.
+type MockRetention struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionMockRecorder
+}
+
+// MockRetentionMockRecorder is the mock recorder for MockRetention.
+type MockRetentionMockRecorder struct {
+	mock *MockRetention
+}
+
+// NewMockRetention creates a new mock instance.
+func NewMockRetention(ctrl *gomock.Controller) *MockRetention {
+	mock := &MockRetention{ctrl: ctrl}
+	mock.recorder = &MockRetentionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetention) EXPECT() *MockRetentionMockRecorder {
+	return m.recorder
+}
+
+// ApplyRetention mocks base method.
+func (m *MockRetention) ApplyRetention(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyRetention", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyRetention indicates an expected call of ApplyRetention.
+func (mr *MockRetentionMockRecorder) ApplyRetention(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyRetention", reflect.TypeOf((*MockRetention)(nil).ApplyRetention), ctx)
+}
+
+// MockRetentionStorage is a mock of RetentionStorage interface.
+type MockRetentionStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionStorageMockRecorder
+}
+
+// MockRetentionStorageMockRecorder is the mock recorder for MockRetentionStorage.
+type MockRetentionStorageMockRecorder struct {
+	mock *MockRetentionStorage
+}
+
+// NewMockRetentionStorage creates a new mock instance.
+func NewMockRetentionStorage(ctrl *gomock.Controller) *MockRetentionStorage {
+	mock := &MockRetentionStorage{ctrl: ctrl}
+	mock.recorder = &MockRetentionStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionStorage) EXPECT() *MockRetentionStorageMockRecorder {
+	return m.recorder
+}
+
+// DeleteDaily mocks base method.
+func (m *MockRetentionStorage) DeleteDaily(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDaily", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDaily indicates an expected call of DeleteDaily.
+func (mr *MockRetentionStorageMockRecorder) DeleteDaily(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDaily", reflect.TypeOf((*MockRetentionStorage)(nil).DeleteDaily), ctx, before)
+}
+
+// RollupHourly mocks base method.
+func (m *MockRetentionStorage) RollupHourly(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupHourly", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollupHourly indicates an expected call of RollupHourly.
+func (mr *MockRetentionStorageMockRecorder) RollupHourly(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupHourly", reflect.TypeOf((*MockRetentionStorage)(nil).RollupHourly), ctx, before)
+}
+
+// RollupRaw mocks base method.
+func (m *MockRetentionStorage) RollupRaw(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupRaw", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollupRaw indicates an expected call of RollupRaw.
+func (mr *MockRetentionStorageMockRecorder) RollupRaw(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupRaw", reflect.TypeOf((*MockRetentionStorage)(nil).RollupRaw), ctx, before)
+}
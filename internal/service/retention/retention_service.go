@@ -0,0 +1,81 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+)
+
+// Policy — сколько хранить данные каждого разрешения; 0 — бессрочно.
+type Policy struct {
+	KeepRaw    time.Duration // сырые точки, затем — часовые агрегаты
+	KeepHourly time.Duration // часовые агрегаты, затем — дневные
+	KeepDaily  time.Duration // дневные агрегаты, затем — удаление
+}
+
+// Validate — каждое следующее разрешение хранится дольше предыдущего
+func (p Policy) Validate() error {
+	if p.KeepRaw < 0 || p.KeepHourly < 0 || p.KeepDaily < 0 {
+		return errors.New("retention: negative keep duration")
+	}
+	if p.KeepHourly > 0 && (p.KeepRaw == 0 || p.KeepHourly <= p.KeepRaw) {
+		return fmt.Errorf("retention: keep_hourly (%s) must be greater than keep_raw (%s)", p.KeepHourly, p.KeepRaw)
+	}
+	if p.KeepDaily > 0 && (p.KeepHourly == 0 || p.KeepDaily <= p.KeepHourly) {
+		return fmt.Errorf("retention: keep_daily (%s) must be greater than keep_hourly (%s)", p.KeepDaily, p.KeepHourly)
+	}
+	return nil
+}
+
+type Service struct {
+	storage interfaces.RetentionStorage
+	policy  Policy
+	logger  *slog.Logger
+}
+
+func NewService(storage interfaces.RetentionStorage, policy Policy, logger *slog.Logger) *Service {
+	return &Service{storage: storage, policy: policy, logger: logger}
+}
+
+// ApplyRetention — свернуть и удалить данные старше сроков хранения.
+// Границы выровнены по часу и суткам (UTC), поэтому агрегаты всегда покрывают полный период.
+func (s *Service) ApplyRetention(ctx context.Context) error {
+	now := utils.NowFunc()
+
+	if s.policy.KeepRaw > 0 {
+		before := now.Add(-s.policy.KeepRaw).Truncate(time.Hour)
+		n, err := s.storage.RollupRaw(ctx, before)
+		if err != nil {
+			s.logger.Error("rollup raw prices failed", "before", before, "err", err)
+			return fmt.Errorf("%w: storage.RollupRaw: %w", errs.ErrInternal, err)
+		}
+		s.logger.Info("raw prices rolled up into hourly", "before", before, "deleted", n)
+	}
+
+	if s.policy.KeepHourly > 0 {
+		before := now.Add(-s.policy.KeepHourly).Truncate(24 * time.Hour)
+		n, err := s.storage.RollupHourly(ctx, before)
+		if err != nil {
+			s.logger.Error("rollup hourly prices failed", "before", before, "err", err)
+			return fmt.Errorf("%w: storage.RollupHourly: %w", errs.ErrInternal, err)
+		}
+		s.logger.Info("hourly prices rolled up into daily", "before", before, "deleted", n)
+	}
+
+	if s.policy.KeepDaily > 0 {
+		before := now.Add(-s.policy.KeepDaily).Truncate(24 * time.Hour)
+		n, err := s.storage.DeleteDaily(ctx, before)
+		if err != nil {
+			s.logger.Error("delete daily prices failed", "before", before, "err", err)
+			return fmt.Errorf("%w: storage.DeleteDaily: %w", errs.ErrInternal, err)
+		}
+		s.logger.Info("daily prices deleted", "before", before, "deleted", n)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	retentionmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/retention/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+const day = 24 * time.Hour
+
+var now = time.Date(2025, 9, 16, 12, 34, 56, 0, time.UTC)
+
+// helper to build service with mocks
+func setupSvc(t *testing.T, policy Policy) (context.Context, *gomock.Controller, *retentionmocks.MockRetentionStorage, *Service) {
+	t.Helper()
+	prev := utils.NowFunc
+	utils.NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { utils.NowFunc = prev })
+
+	ctrl := gomock.NewController(t)
+	storage := retentionmocks.NewMockRetentionStorage(ctrl)
+	return context.Background(), ctrl, storage, NewService(storage, policy, slog.Default())
+}
+
+func TestApplyRetention_AlignedCutoffs(t *testing.T) {
+	ctx, ctrl, storage, svc := setupSvc(t, Policy{KeepRaw: 30 * day, KeepHourly: 365 * day, KeepDaily: 3 * 365 * day})
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		// границы выровнены: по часу для сырых точек, по суткам для агрегатов
+		storage.EXPECT().RollupRaw(gomock.Any(), time.Date(2025, 8, 17, 12, 0, 0, 0, time.UTC)).Return(int64(8640), nil),
+		storage.EXPECT().RollupHourly(gomock.Any(), time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC)).Return(int64(24), nil),
+		storage.EXPECT().DeleteDaily(gomock.Any(), time.Date(2022, 9, 17, 0, 0, 0, 0, time.UTC)).Return(int64(1), nil),
+	)
+
+	if err := svc.ApplyRetention(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestApplyRetention_KeepForever(t *testing.T) {
+	ctx, ctrl, storage, svc := setupSvc(t, Policy{KeepRaw: 30 * day})
+	defer ctrl.Finish()
+
+	// часовые и дневные агрегаты бессрочно — только свёртка сырых точек
+	storage.EXPECT().RollupRaw(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+	if err := svc.ApplyRetention(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestApplyRetention_StorageError(t *testing.T) {
+	ctx, ctrl, storage, svc := setupSvc(t, Policy{KeepRaw: 30 * day, KeepHourly: 365 * day})
+	defer ctrl.Finish()
+
+	// после ошибки свёртки сырых точек следующие шаги не выполняются
+	storage.EXPECT().RollupRaw(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("db down"))
+
+	if err := svc.ApplyRetention(ctx); !errors.Is(err, derrors.ErrInternal) {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	valid := []Policy{
+		{},
+		{KeepRaw: 30 * day},
+		{KeepRaw: 30 * day, KeepHourly: 365 * day},
+		{KeepRaw: 30 * day, KeepHourly: 365 * day, KeepDaily: 1000 * day},
+	}
+	for _, p := range valid {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", p, err)
+		}
+	}
+	invalid := []Policy{
+		{KeepRaw: -day},
+		{KeepHourly: 365 * day},
+		{KeepRaw: 30 * day, KeepHourly: 7 * day},
+		{KeepRaw: 30 * day, KeepDaily: 365 * day},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("%+v: expected error", p)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/subscriptions.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSubscriptions is a mock of Subscriptions interface.
+type MockSubscriptions struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionsMockRecorder
+}
+
+// MockSubscriptionsMockRecorder is the mock recorder for MockSubscriptions.
+type MockSubscriptionsMockRecorder struct {
+	mock *MockSubscriptions
+}
+
+// NewMockSubscriptions creates a new mock instance.
+func NewMockSubscriptions(ctrl *gomock.Controller) *MockSubscriptions {
+	mock := &MockSubscriptions{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptions) EXPECT() *MockSubscriptionsMockRecorder {
+	return m.recorder
+}
+
+// FindDue mocks base method.
+func (m *MockSubscriptions) FindDue(ctx context.Context, now time.Time) ([]domain.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, now)
+	ret0, _ := ret[0].([]domain.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockSubscriptionsMockRecorder) FindDue(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockSubscriptions)(nil).FindDue), ctx, now)
+}
+
+// GetSubscription mocks base method.
+func (m *MockSubscriptions) GetSubscription(ctx context.Context, chatID int64) (domain.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, chatID)
+	ret0, _ := ret[0].(domain.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockSubscriptionsMockRecorder) GetSubscription(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockSubscriptions)(nil).GetSubscription), ctx, chatID)
+}
+
+// MarkDisabled mocks base method.
+func (m *MockSubscriptions) MarkDisabled(ctx context.Context, chatID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDisabled", ctx, chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDisabled indicates an expected call of MarkDisabled.
+func (mr *MockSubscriptionsMockRecorder) MarkDisabled(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDisabled", reflect.TypeOf((*MockSubscriptions)(nil).MarkDisabled), ctx, chatID)
+}
+
+// MarkEnabled mocks base method.
+func (m *MockSubscriptions) MarkEnabled(ctx context.Context, sub domain.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEnabled", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEnabled indicates an expected call of MarkEnabled.
+func (mr *MockSubscriptionsMockRecorder) MarkEnabled(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEnabled", reflect.TypeOf((*MockSubscriptions)(nil).MarkEnabled), ctx, sub)
+}
+
+// MarkMissed mocks base method.
+func (m *MockSubscriptions) MarkMissed(ctx context.Context, chatID int64, at, next time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMissed", ctx, chatID, at, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkMissed indicates an expected call of MarkMissed.
+func (mr *MockSubscriptionsMockRecorder) MarkMissed(ctx, chatID, at, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMissed", reflect.TypeOf((*MockSubscriptions)(nil).MarkMissed), ctx, chatID, at, next)
+}
+
+// MarkSent mocks base method.
+func (m *MockSubscriptions) MarkSent(ctx context.Context, chatID int64, at, next time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSent", ctx, chatID, at, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSent indicates an expected call of MarkSent.
+func (mr *MockSubscriptionsMockRecorder) MarkSent(ctx, chatID, at, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSent", reflect.TypeOf((*MockSubscriptions)(nil).MarkSent), ctx, chatID, at, next)
+}
+
+// QuietHours mocks base method.
+func (m *MockSubscriptions) QuietHours(ctx context.Context, chatID int64) (domain.QuietHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuietHours", ctx, chatID)
+	ret0, _ := ret[0].(domain.QuietHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuietHours indicates an expected call of QuietHours.
+func (mr *MockSubscriptionsMockRecorder) QuietHours(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuietHours", reflect.TypeOf((*MockSubscriptions)(nil).QuietHours), ctx, chatID)
+}
+
+// Reschedule mocks base method.
+func (m *MockSubscriptions) Reschedule(ctx context.Context, chatID int64, next time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, chatID, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockSubscriptionsMockRecorder) Reschedule(ctx, chatID, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockSubscriptions)(nil).Reschedule), ctx, chatID, next)
+}
+
+// SetQuietHours mocks base method.
+func (m *MockSubscriptions) SetQuietHours(ctx context.Context, chatID int64, q domain.QuietHours) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetQuietHours", ctx, chatID, q)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetQuietHours indicates an expected call of SetQuietHours.
+func (mr *MockSubscriptionsMockRecorder) SetQuietHours(ctx, chatID, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQuietHours", reflect.TypeOf((*MockSubscriptions)(nil).SetQuietHours), ctx, chatID, q)
+}
+
+// SetTimezone mocks base method.
+func (m *MockSubscriptions) SetTimezone(ctx context.Context, chatID int64, tz string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTimezone", ctx, chatID, tz)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTimezone indicates an expected call of SetTimezone.
+func (mr *MockSubscriptionsMockRecorder) SetTimezone(ctx, chatID, tz interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimezone", reflect.TypeOf((*MockSubscriptions)(nil).SetTimezone), ctx, chatID, tz)
+}
+
+// Timezone mocks base method.
+func (m *MockSubscriptions) Timezone(ctx context.Context, chatID int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timezone", ctx, chatID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Timezone indicates an expected call of Timezone.
+func (mr *MockSubscriptionsMockRecorder) Timezone(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timezone", reflect.TypeOf((*MockSubscriptions)(nil).Timezone), ctx, chatID)
+}
+
+// MockSubscriptionCommander is a mock of SubscriptionCommander interface.
+type MockSubscriptionCommander struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionCommanderMockRecorder
+}
+
+// MockSubscriptionCommanderMockRecorder is the mock recorder for MockSubscriptionCommander.
+type MockSubscriptionCommanderMockRecorder struct {
+	mock *MockSubscriptionCommander
+}
+
+// NewMockSubscriptionCommander creates a new mock instance.
+func NewMockSubscriptionCommander(ctrl *gomock.Controller) *MockSubscriptionCommander {
+	mock := &MockSubscriptionCommander{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionCommanderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionCommander) EXPECT() *MockSubscriptionCommanderMockRecorder {
+	return m.recorder
+}
+
+// Disable mocks base method.
+func (m *MockSubscriptionCommander) Disable(ctx context.Context, chatID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockSubscriptionCommanderMockRecorder) Disable(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockSubscriptionCommander)(nil).Disable), ctx, chatID)
+}
+
+// Enable mocks base method.
+func (m *MockSubscriptionCommander) Enable(ctx context.Context, chatID int64, sched domain.Schedule, coins []string) (domain.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, chatID, sched, coins)
+	ret0, _ := ret[0].(domain.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockSubscriptionCommanderMockRecorder) Enable(ctx, chatID, sched, coins interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockSubscriptionCommander)(nil).Enable), ctx, chatID, sched, coins)
+}
+
+// QuietHours mocks base method.
+func (m *MockSubscriptionCommander) QuietHours(ctx context.Context, chatID int64) (domain.QuietHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuietHours", ctx, chatID)
+	ret0, _ := ret[0].(domain.QuietHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuietHours indicates an expected call of QuietHours.
+func (mr *MockSubscriptionCommanderMockRecorder) QuietHours(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuietHours", reflect.TypeOf((*MockSubscriptionCommander)(nil).QuietHours), ctx, chatID)
+}
+
+// SetQuietHours mocks base method.
+func (m *MockSubscriptionCommander) SetQuietHours(ctx context.Context, chatID int64, q domain.QuietHours) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetQuietHours", ctx, chatID, q)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetQuietHours indicates an expected call of SetQuietHours.
+func (mr *MockSubscriptionCommanderMockRecorder) SetQuietHours(ctx, chatID, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQuietHours", reflect.TypeOf((*MockSubscriptionCommander)(nil).SetQuietHours), ctx, chatID, q)
+}
+
+// SetTimezone mocks base method.
+func (m *MockSubscriptionCommander) SetTimezone(ctx context.Context, chatID int64, tz string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTimezone", ctx, chatID, tz)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTimezone indicates an expected call of SetTimezone.
+func (mr *MockSubscriptionCommanderMockRecorder) SetTimezone(ctx, chatID, tz interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimezone", reflect.TypeOf((*MockSubscriptionCommander)(nil).SetTimezone), ctx, chatID, tz)
+}
+
+// Timezone mocks base method.
+func (m *MockSubscriptionCommander) Timezone(ctx context.Context, chatID int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timezone", ctx, chatID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Timezone indicates an expected call of Timezone.
+func (mr *MockSubscriptionCommanderMockRecorder) Timezone(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timezone", reflect.TypeOf((*MockSubscriptionCommander)(nil).Timezone), ctx, chatID)
+}
+
+// MockSubscriptionDispatcher is a mock of SubscriptionDispatcher interface.
+type MockSubscriptionDispatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionDispatcherMockRecorder
+}
+
+// MockSubscriptionDispatcherMockRecorder is the mock recorder for MockSubscriptionDispatcher.
+type MockSubscriptionDispatcherMockRecorder struct {
+	mock *MockSubscriptionDispatcher
+}
+
+// NewMockSubscriptionDispatcher creates a new mock instance.
+func NewMockSubscriptionDispatcher(ctrl *gomock.Controller) *MockSubscriptionDispatcher {
+	mock := &MockSubscriptionDispatcher{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionDispatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionDispatcher) EXPECT() *MockSubscriptionDispatcherMockRecorder {
+	return m.recorder
+}
+
+// DispatchDue mocks base method.
+func (m *MockSubscriptionDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchDue", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DispatchDue indicates an expected call of DispatchDue.
+func (mr *MockSubscriptionDispatcherMockRecorder) DispatchDue(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchDue", reflect.TypeOf((*MockSubscriptionDispatcher)(nil).DispatchDue), ctx)
+}
@@ -3,50 +3,142 @@ package subscription
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/botfmt"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/schedule"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
-	"gopkg.in/telebot.v4"
+	"github.com/jackc/pgx/v5"
 )
 
+// DispatchOptions — политика рассылки по сохранённым ценам
+type DispatchOptions struct {
+	StaleAfter time.Duration // цена старше считается устаревшей (0 — не проверять)
+	SkipStale  bool          // true — не отправлять устаревшие цены, false — отправлять с пометкой
+}
+
 type Service struct {
-	bot            *telebot.Bot
-	repo           interfaces.Subscriptions
-	cryptoProvider interfaces.CryptoProvider
-	log            *slog.Logger
-	fetchTimeout   time.Duration
+	notifier     interfaces.Notifier
+	repo         interfaces.Subscriptions
+	rates        interfaces.Service
+	history      interfaces.PriceHistory // цены на начало тихих часов для сводки
+	opts         DispatchOptions
+	log          *slog.Logger
+	fetchTimeout time.Duration
 }
 
-func New(bot *telebot.Bot, repo interfaces.Subscriptions, cryptoProvider interfaces.CryptoProvider, log *slog.Logger) *Service {
+func New(notifier interfaces.Notifier, repo interfaces.Subscriptions, rates interfaces.Service, history interfaces.PriceHistory, opts DispatchOptions, log *slog.Logger) *Service {
 	return &Service{
-		bot:            bot,
-		repo:           repo,
-		cryptoProvider: cryptoProvider,
-		log:            log,
-		fetchTimeout:   4 * time.Second,
+		notifier:     notifier,
+		repo:         repo,
+		rates:        rates,
+		history:      history,
+		opts:         opts,
+		log:          log,
+		fetchTimeout: 4 * time.Second,
 	}
 }
 
-// Enable включает авторассылку для чата.
+// Enable включает авторассылку для чата по расписанию sched: coins — символы монет (пустой — все отслеживаемые).
+// Интервальная подписка отправляется на ближайшем тике, daily и cron — в ближайшее время расписания
+// по часовому поясу чата.
 // Идемпотентна: повторный вызов с теми же параметрами безопасен.
-func (s *Service) Enable(ctx context.Context, chatID int64, intervalMinutes int) error {
-	if intervalMinutes <= 0 {
-		return errors.New("interval must be > 0")
+func (s *Service) Enable(ctx context.Context, chatID int64, sched domain.Schedule, coins []string) (domain.Subscription, error) {
+	if err := schedule.Validate(sched); err != nil {
+		return domain.Subscription{}, fmt.Errorf("%w: %w", errs.ErrInvalidArgument, err)
+	}
+	sub := domain.Subscription{ChatID: chatID, Schedule: sched}
+	if len(coins) > 0 {
+		tracked, err := s.rates.TrackedCoins(ctx)
+		if err != nil {
+			return domain.Subscription{}, err
+		}
+		for _, c := range coins {
+			c = strings.ToUpper(strings.TrimSpace(c))
+			if !slices.ContainsFunc(tracked, func(info domain.CoinInfo) bool { return info.Symbol == c }) {
+				return domain.Subscription{}, fmt.Errorf("%w: %s", errs.ErrCoinNotFound, c)
+			}
+			if !slices.Contains(sub.Coins, c) {
+				sub.Coins = append(sub.Coins, c)
+			}
+		}
+	}
+	tz, err := s.repo.Timezone(ctx, chatID)
+	if err != nil {
+		return domain.Subscription{}, fmt.Errorf("%w: storage.Timezone(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	sub.Timezone = tz
+	sub.NextRun = utils.NowFunc()
+	if sched.Kind != domain.ScheduleInterval {
+		if sub.NextRun, err = schedule.Next(sched, sub.NextRun, s.location(tz)); err != nil {
+			return domain.Subscription{}, fmt.Errorf("%w: %w", errs.ErrInvalidArgument, err)
+		}
 	}
-	if err := s.repo.MarkEnabled(ctx, chatID, intervalMinutes); err != nil {
+	if err := s.repo.MarkEnabled(ctx, sub); err != nil {
 		s.log.Error("subscriptions.enable failed",
 			slog.Int64("chat_id", chatID),
-			slog.Int("interval_min", intervalMinutes),
+			slog.String("schedule", string(sched.Kind)),
 			slog.String("err", err.Error()))
-		return err
+		return domain.Subscription{}, err
 	}
 	s.log.Info("subscriptions.enable ok",
 		slog.Int64("chat_id", chatID),
-		slog.Int("interval_min", intervalMinutes))
+		slog.String("schedule", string(sched.Kind)),
+		slog.Duration("interval", sched.Interval),
+		slog.String("spec", sched.Spec),
+		slog.String("tz", tz),
+		slog.Time("next_run", sub.NextRun),
+		slog.Any("coins", sub.Coins))
+	return sub, nil
+}
+
+// Timezone возвращает часовой пояс чата ("UTC", если не задан).
+func (s *Service) Timezone(ctx context.Context, chatID int64) (string, error) {
+	tz, err := s.repo.Timezone(ctx, chatID)
+	if err != nil {
+		return "", fmt.Errorf("%w: storage.Timezone(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	return tz, nil
+}
+
+// SetTimezone задаёт часовой пояс чата (имя IANA, например Europe/Moscow).
+// Включённая подписка daily/cron перепланируется на ближайшее время по новому поясу.
+func (s *Service) SetTimezone(ctx context.Context, chatID int64, tz string) error {
+	tz = strings.TrimSpace(tz)
+	loc, err := time.LoadLocation(tz)
+	if err != nil || tz == "" || tz == "Local" {
+		return fmt.Errorf("%w: unknown timezone %q", errs.ErrInvalidArgument, tz)
+	}
+	if err := s.repo.SetTimezone(ctx, chatID, loc.String()); err != nil {
+		return fmt.Errorf("%w: storage.SetTimezone(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	s.log.Info("subscriptions.timezone_set", slog.Int64("chat_id", chatID), slog.String("tz", loc.String()))
+
+	sub, err := s.repo.GetSubscription(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("%w: storage.GetSubscription(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	if sub.Schedule.Kind == domain.ScheduleInterval {
+		return nil
+	}
+	next, err := schedule.Next(sub.Schedule, utils.NowFunc(), loc)
+	if err != nil {
+		return fmt.Errorf("%w: schedule.Next(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	if err := s.repo.Reschedule(ctx, chatID, next); err != nil {
+		return fmt.Errorf("%w: storage.Reschedule(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	s.log.Info("subscriptions.rescheduled", slog.Int64("chat_id", chatID), slog.Time("next_run", next))
 	return nil
 }
 
@@ -63,74 +155,231 @@ func (s *Service) Disable(ctx context.Context, chatID int64) error {
 	return nil
 }
 
+// QuietHours возвращает тихие часы чата (нулевое значение — не заданы).
+func (s *Service) QuietHours(ctx context.Context, chatID int64) (domain.QuietHours, error) {
+	q, err := s.repo.QuietHours(ctx, chatID)
+	if err != nil {
+		return domain.QuietHours{}, fmt.Errorf("%w: storage.QuietHours(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	return q, nil
+}
+
+// SetQuietHours задаёт тихие часы подписки чата по его часовому поясу; нулевое значение — выключить.
+// Тихие часы хранятся с подпиской, поэтому без /startauto их некуда сохранить — ErrSubscriptionNotFound.
+// В тихие часы рассылки не отправляются (см. DispatchDue).
+func (s *Service) SetQuietHours(ctx context.Context, chatID int64, q domain.QuietHours) error {
+	if q != (domain.QuietHours{}) {
+		if err := schedule.ValidateQuiet(q); err != nil {
+			return fmt.Errorf("%w: %w", errs.ErrInvalidArgument, err)
+		}
+	}
+	if err := s.repo.SetQuietHours(ctx, chatID, q); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errs.ErrSubscriptionNotFound
+		}
+		return fmt.Errorf("%w: storage.SetQuietHours(%d): %w", errs.ErrInternal, chatID, err)
+	}
+	s.log.Info("subscriptions.quiet_hours_set",
+		slog.Int64("chat_id", chatID),
+		slog.Duration("start", q.Start),
+		slog.Duration("end", q.End),
+		slog.Bool("summary", q.Summary))
+	return nil
+}
+
 // DispatchDue выполняет одну итерацию авторассылки:
-//  1. Находит чаты, у которых истёк интервал (due).
-//  2. Получает свежие курсы криптовалют.
-//  3. Формирует компактное сообщение (строки line).
-//  4. Отправляет его каждому due-чату.
-//  5. Отмечает отправку в репозитории.
+//  1. Находит подписки, у которых наступило время рассылки (due).
+//  2. Берёт последние сохранённые цены (те же, что отдаёт /rates).
+//  3. Группирует подписки по набору монет: сообщение собирается один раз на набор.
+//  4. Отбрасывает или помечает устаревшие цены (см. DispatchOptions).
+//  5. Отправляет сообщение каждому чату группы, отмечает отправку и планирует следующую рассылку:
+//     интервал — от момента отправки, daily и cron — ближайшее время расписания после now
+//     по часовому поясу чата (пропущенные из-за простоя рассылки не догоняются).
+//
+// В тихие часы чата рассылка не отправляется, а откладывается: интервальная — до конца тишины,
+// daily и cron — до следующего времени расписания (со сводкой — не позже конца тишины).
+// Если у чата включена сводка, первая рассылка после тихих часов начинается со сводки пропущенного:
+// как изменилась цена каждой монеты подписки с первой пропущенной рассылки.
 //
 // Возвращает количество успешно отправленных сообщений.
 func (s *Service) DispatchDue(ctx context.Context) (sent int, err error) {
 	now := utils.NowFunc()
 	s.log.Debug("subscriptions.loading_due", slog.Time("now", now))
 
-	chatIDs, err := s.repo.FindDue(ctx, now)
+	subs, err := s.repo.FindDue(ctx, now)
 	if err != nil {
 		s.log.Error("subscriptions.find_due failed", slog.String("err", err.Error()))
 		return 0, err
 	}
-	if len(chatIDs) == 0 {
+	if len(subs) == 0 {
 		s.log.Debug("subscriptions.no_due")
 		return 0, nil
 	}
 
-	// Получаем курсы с коротким таймаутом
+	// Читаем сохранённые цены с коротким таймаутом
 	rCtx, cancel := context.WithTimeout(ctx, s.fetchTimeout)
 	defer cancel()
 
-	start := time.Now()
-	rates, err := s.cryptoProvider.FetchRates(rCtx)
+	rates, err := s.rates.GetLatest(rCtx, "")
 	if err != nil {
-		s.log.Error("subscriptions.fetch_rates failed", slog.String("err", err.Error()))
+		if errors.Is(err, errs.ErrPriceNotFound) {
+			s.log.Warn("subscriptions.no_stored_prices")
+			return 0, nil
+		}
+		s.log.Error("subscriptions.get_latest failed", slog.String("err", err.Error()))
 		return 0, err
 	}
-	if len(rates) == 0 {
-		s.log.Warn("subscriptions.empty_rates")
-		return 0, nil
-	}
-	s.log.Debug("subscriptions.rates_fetched",
-		slog.Int("count", len(rates)),
-		slog.Duration("duration", time.Since(start)))
 
-	// Используем форматтер из format.go
-	var b strings.Builder
-	for i, r := range rates {
-		if i > 0 {
-			b.WriteByte('\n')
+	// сообщения по наборам монет: ключ — отсортированные символы через запятую, "" — все монеты
+	messages := make(map[string]string)
+	quiet := 0
+	for _, sub := range subs {
+		loc := s.location(sub.Timezone)
+		if until := schedule.QuietUntil(sub.Quiet, now, loc); !until.IsZero() {
+			s.deferQuiet(ctx, sub, loc, now, until)
+			quiet++
+			continue
 		}
-		b.WriteString(botfmt.FormatRateLine(r))
-	}
-	msg := b.String()
-
-	sent = 0
-	for _, chatID := range chatIDs {
-		if _, err := s.bot.Send(&telebot.Chat{ID: chatID}, msg); err != nil {
+		key := coinSetKey(sub.Coins)
+		msg, ok := messages[key]
+		if !ok {
+			msg = s.buildMessage(rates, sub.Coins, now)
+			messages[key] = msg
+		}
+		if msg == "" {
+			// Не отмечаем отправку: чат получит рассылку, когда цены обновятся
+			continue
+		}
+		next, err := schedule.Next(sub.Schedule, now, loc)
+		if err != nil {
+			s.log.Error("subscriptions.next_run failed",
+				slog.Int64("chat_id", sub.ChatID),
+				slog.String("spec", sub.Schedule.Spec),
+				slog.String("err", err.Error()))
+			continue
+		}
+		text := msg
+		if sub.Quiet.Summary && !sub.MissedSince.IsZero() {
+			text = s.quietSummary(ctx, rates, sub, loc) + "\n\n" + msg
+		}
+		if err := s.notifier.Notify(ctx, sub.ChatID, text); err != nil {
 			s.log.Error("subscriptions.send failed",
-				slog.Int64("chat_id", chatID),
+				slog.Int64("chat_id", sub.ChatID),
 				slog.String("err", err.Error()))
 			continue
 		}
-		if err := s.repo.MarkSent(ctx, chatID, now); err != nil {
+		if err := s.repo.MarkSent(ctx, sub.ChatID, now, next); err != nil {
 			s.log.Error("subscriptions.mark_sent failed",
-				slog.Int64("chat_id", chatID),
+				slog.Int64("chat_id", sub.ChatID),
 				slog.String("err", err.Error()))
 			continue
 		}
 		sent++
 	}
 	s.log.Info("subscriptions.dispatch_done",
-		slog.Int("due", len(chatIDs)),
+		slog.Int("due", len(subs)),
+		slog.Int("coin_sets", len(messages)),
+		slog.Int("quiet", quiet),
 		slog.Int("sent", sent))
 	return sent, nil
 }
+
+// deferQuiet — отложить рассылку, выпавшую на тихие часы (заканчиваются в until)
+func (s *Service) deferQuiet(ctx context.Context, sub domain.Subscription, loc *time.Location, now, until time.Time) {
+	next := until
+	if sub.Schedule.Kind != domain.ScheduleInterval {
+		regular, err := schedule.Next(sub.Schedule, now, loc)
+		if err != nil {
+			s.log.Error("subscriptions.next_run failed",
+				slog.Int64("chat_id", sub.ChatID),
+				slog.String("spec", sub.Schedule.Spec),
+				slog.String("err", err.Error()))
+			return
+		}
+		// без сводки пропущенное время расписания просто пропускается
+		if !sub.Quiet.Summary || regular.Before(until) {
+			next = regular
+		}
+	}
+	if err := s.repo.MarkMissed(ctx, sub.ChatID, now, next); err != nil {
+		s.log.Error("subscriptions.mark_missed failed",
+			slog.Int64("chat_id", sub.ChatID),
+			slog.String("err", err.Error()))
+		return
+	}
+	s.log.Debug("subscriptions.quiet_deferred",
+		slog.Int64("chat_id", sub.ChatID),
+		slog.Time("next_run", next))
+}
+
+// quietSummary — сводка за тихие часы: цены монет подписки на момент первой пропущенной рассылки и сейчас.
+// Ошибка чтения истории не мешает рассылке — по монете выводится «н/д».
+func (s *Service) quietSummary(ctx context.Context, rates []domain.Coin, sub domain.Subscription, loc *time.Location) string {
+	var was, cur []domain.Coin
+	for _, r := range rates {
+		if len(sub.Coins) > 0 && !slices.Contains(sub.Coins, r.Symbol) {
+			continue
+		}
+		var at domain.Coin
+		prices, err := s.history.PricesAt(ctx, r.Symbol, r.Currency, []time.Time{sub.MissedSince})
+		if err != nil {
+			s.log.Warn("subscriptions.summary_price failed",
+				slog.Int64("chat_id", sub.ChatID),
+				slog.String("symbol", r.Symbol),
+				slog.String("err", err.Error()))
+		} else if len(prices) == 1 {
+			at = prices[0]
+		}
+		was = append(was, at)
+		cur = append(cur, r)
+	}
+	return botfmt.FormatQuietSummary(sub.MissedSince.In(loc), was, cur)
+}
+
+// location — часовой пояс чата; неизвестный (не должен попасть в БД) — UTC
+func (s *Service) location(tz string) *time.Location {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		s.log.Warn("subscriptions.unknown_timezone", slog.String("tz", tz), slog.String("err", err.Error()))
+		return time.UTC
+	}
+	return loc
+}
+
+// buildMessage — строки FormatRateLine по монетам coins (пустой — все); "" — нечего отправлять
+func (s *Service) buildMessage(rates []domain.Coin, coins []string, now time.Time) string {
+	var b strings.Builder
+	lines := 0
+	for _, r := range rates {
+		if len(coins) > 0 && !slices.Contains(coins, r.Symbol) {
+			continue
+		}
+		age := now.Sub(r.UpdatedAt)
+		stale := s.opts.StaleAfter > 0 && age > s.opts.StaleAfter
+		if stale && s.opts.SkipStale {
+			s.log.Warn("subscriptions.stale_price_skipped",
+				slog.String("symbol", r.Symbol),
+				slog.Duration("age", age))
+			continue
+		}
+		if lines > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(botfmt.FormatRateLine(r))
+		if stale {
+			b.WriteString(botfmt.FormatStaleMark(age))
+		}
+		lines++
+	}
+	if lines == 0 {
+		s.log.Warn("subscriptions.all_prices_stale", slog.Any("coins", coins))
+	}
+	return b.String()
+}
+
+// coinSetKey — ключ набора монет независимо от порядка: [SOL ETH] и [ETH SOL] — одна группа
+func coinSetKey(coins []string) string {
+	sorted := slices.Clone(coins)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",")
+}
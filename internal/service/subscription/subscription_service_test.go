@@ -0,0 +1,296 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	derrors "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/botfmt"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	alertsmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/alerts/mocks"
+	ratesmocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/rates/mocks"
+	submocks "github.com/NastyaGoryachaya/crypto-rate-service/internal/service/subscription/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	now     = time.Date(2025, 9, 16, 12, 0, 0, 0, time.UTC)
+	every10 = domain.Schedule{Kind: domain.ScheduleInterval, Interval: 10 * time.Minute}
+)
+
+type deps struct {
+	repo     *submocks.MockSubscriptions
+	rates    *ratesmocks.MockService
+	history  *alertsmocks.MockPriceHistory
+	notifier *alertsmocks.MockNotifier
+}
+
+// helper to build service with mocks
+func setupSvc(t *testing.T, opts DispatchOptions) (context.Context, *gomock.Controller, deps, *Service) {
+	t.Helper()
+	prev := utils.NowFunc
+	utils.NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { utils.NowFunc = prev })
+
+	ctrl := gomock.NewController(t)
+	d := deps{
+		repo:     submocks.NewMockSubscriptions(ctrl),
+		rates:    ratesmocks.NewMockService(ctrl),
+		history:  alertsmocks.NewMockPriceHistory(ctrl),
+		notifier: alertsmocks.NewMockNotifier(ctrl),
+	}
+	return context.Background(), ctrl, d, New(d.notifier, d.repo, d.rates, d.history, opts, slog.Default())
+}
+
+func latest(updatedAt time.Time, symbols ...string) []domain.Coin {
+	out := make([]domain.Coin, 0, len(symbols))
+	for _, s := range symbols {
+		out = append(out, domain.Coin{Symbol: s, Price: decimal.NewFromInt(100), Currency: "usd", UpdatedAt: updatedAt})
+	}
+	return out
+}
+
+func TestEnable_NormalizesCoins(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	d.rates.EXPECT().TrackedCoins(gomock.Any()).Return([]domain.CoinInfo{{Symbol: "BTC"}, {Symbol: "ETH"}, {Symbol: "SOL"}}, nil)
+	d.repo.EXPECT().Timezone(gomock.Any(), int64(1)).Return("UTC", nil)
+	// интервальная подписка — первая рассылка на ближайшем тике
+	d.repo.EXPECT().MarkEnabled(gomock.Any(), domain.Subscription{
+		ChatID: 1, Schedule: every10, Coins: []string{"ETH", "SOL"}, Timezone: "UTC", NextRun: now,
+	}).Return(nil)
+
+	sub, err := svc.Enable(ctx, 1, every10, []string{"eth", "SOL", "Eth"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sub.Coins) != 2 {
+		t.Fatalf("unexpected subscription: %+v", sub)
+	}
+}
+
+func TestEnable_UnknownCoin(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	d.rates.EXPECT().TrackedCoins(gomock.Any()).Return([]domain.CoinInfo{{Symbol: "BTC"}}, nil)
+
+	if _, err := svc.Enable(ctx, 1, every10, []string{"DOGE"}); !errors.Is(err, derrors.ErrCoinNotFound) {
+		t.Fatalf("expected ErrCoinNotFound, got %v", err)
+	}
+}
+
+func TestEnable_DailyInChatTimezone(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	daily := domain.Schedule{Kind: domain.ScheduleDaily, Spec: "09:00"}
+	d.repo.EXPECT().Timezone(gomock.Any(), int64(1)).Return("Europe/Moscow", nil)
+	// 12:00 UTC = 15:00 по Москве — ближайшие 09:00 MSK завтра в 06:00 UTC
+	d.repo.EXPECT().MarkEnabled(gomock.Any(), domain.Subscription{
+		ChatID: 1, Schedule: daily, Timezone: "Europe/Moscow", NextRun: time.Date(2025, 9, 17, 6, 0, 0, 0, time.UTC),
+	}).Return(nil)
+
+	if _, err := svc.Enable(ctx, 1, daily, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEnable_InvalidSchedule(t *testing.T) {
+	ctx, ctrl, _, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	for _, sched := range []domain.Schedule{
+		{Kind: domain.ScheduleInterval},
+		{Kind: domain.ScheduleCron, Spec: "0 25 * * *"},
+	} {
+		if _, err := svc.Enable(ctx, 1, sched, nil); !errors.Is(err, derrors.ErrInvalidArgument) {
+			t.Errorf("%+v: expected ErrInvalidArgument, got %v", sched, err)
+		}
+	}
+}
+
+func TestSetTimezone_ReschedulesDaily(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	daily := domain.Schedule{Kind: domain.ScheduleDaily, Spec: "18:00"}
+	d.repo.EXPECT().SetTimezone(gomock.Any(), int64(1), "Asia/Tokyo").Return(nil)
+	d.repo.EXPECT().GetSubscription(gomock.Any(), int64(1)).Return(domain.Subscription{ChatID: 1, Schedule: daily, Timezone: "Asia/Tokyo"}, nil)
+	// 12:00 UTC = 21:00 в Токио — следующие 18:00 JST завтра в 09:00 UTC
+	d.repo.EXPECT().Reschedule(gomock.Any(), int64(1), time.Date(2025, 9, 17, 9, 0, 0, 0, time.UTC)).Return(nil)
+
+	if err := svc.SetTimezone(ctx, 1, "Asia/Tokyo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSetTimezone_Invalid(t *testing.T) {
+	ctx, ctrl, _, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	for _, tz := range []string{"", "Local", "Mars/Olympus"} {
+		if err := svc.SetTimezone(ctx, 1, tz); !errors.Is(err, derrors.ErrInvalidArgument) {
+			t.Errorf("%q: expected ErrInvalidArgument, got %v", tz, err)
+		}
+	}
+}
+
+func TestDispatchDue_MessagePerCoinSet(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	d.repo.EXPECT().FindDue(gomock.Any(), now).Return([]domain.Subscription{
+		{ChatID: 1, Schedule: every10},
+		{ChatID: 2, Schedule: every10, Coins: []string{"SOL", "ETH"}},
+		{ChatID: 3, Schedule: every10, Coins: []string{"ETH", "SOL"}},
+	}, nil)
+	d.rates.EXPECT().GetLatest(gomock.Any(), "").Return(latest(now, "BTC", "ETH", "SOL"), nil)
+
+	got := map[int64]string{}
+	d.notifier.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any()).Times(3).DoAndReturn(
+		func(_ context.Context, chatID int64, text string) error {
+			got[chatID] = text
+			return nil
+		})
+	d.repo.EXPECT().MarkSent(gomock.Any(), gomock.Any(), now, now.Add(10*time.Minute)).Times(3).Return(nil)
+
+	sent, err := svc.DispatchDue(ctx)
+	if err != nil || sent != 3 {
+		t.Fatalf("unexpected result: sent=%d err=%v", sent, err)
+	}
+	all := latest(now, "BTC", "ETH", "SOL")
+	// порядок монет в подписке не важен — чаты 2 и 3 получают одно и то же сообщение без BTC
+	if want := rateLines(all[1], all[2]); got[2] != want || got[3] != want {
+		t.Fatalf("unexpected coin set messages:\n%q\n%q", got[2], got[3])
+	}
+	if want := rateLines(all...); got[1] != want {
+		t.Fatalf("unexpected full message: %q", got[1])
+	}
+}
+
+func TestDispatchDue_SkipStaleKeepsDue(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{StaleAfter: 15 * time.Minute, SkipStale: true})
+	defer ctrl.Finish()
+
+	// у ETH цена устарела — подписка только на ETH не отправляется и не отмечается
+	prices := append(latest(now, "BTC"), latest(now.Add(-time.Hour), "ETH")...)
+	d.repo.EXPECT().FindDue(gomock.Any(), now).Return([]domain.Subscription{{ChatID: 1, Schedule: every10, Coins: []string{"ETH"}}, {ChatID: 2, Schedule: every10}}, nil)
+	d.rates.EXPECT().GetLatest(gomock.Any(), "").Return(prices, nil)
+	d.notifier.EXPECT().Notify(gomock.Any(), int64(2), rateLines(prices[0])).Return(nil)
+	d.repo.EXPECT().MarkSent(gomock.Any(), int64(2), now, now.Add(10*time.Minute)).Return(nil)
+
+	if sent, err := svc.DispatchDue(ctx); err != nil || sent != 1 {
+		t.Fatalf("unexpected result: sent=%d err=%v", sent, err)
+	}
+}
+
+func TestDispatchDue_NextRunByChatTimezone(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	weekdays := domain.Schedule{Kind: domain.ScheduleCron, Spec: "0 15 * * 1-5"}
+	d.repo.EXPECT().FindDue(gomock.Any(), now).Return([]domain.Subscription{
+		{ChatID: 1, Schedule: weekdays, Timezone: "Europe/Moscow"},
+	}, nil)
+	d.rates.EXPECT().GetLatest(gomock.Any(), "").Return(latest(now, "BTC"), nil)
+	d.notifier.EXPECT().Notify(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	// вторник 15:00 MSK отправлен — следующая рассылка в среду в 15:00 MSK (12:00 UTC)
+	d.repo.EXPECT().MarkSent(gomock.Any(), int64(1), now, time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)).Return(nil)
+
+	if sent, err := svc.DispatchDue(ctx); err != nil || sent != 1 {
+		t.Fatalf("unexpected result: sent=%d err=%v", sent, err)
+	}
+}
+
+// rateLines — ожидаемое сообщение рассылки по ценам rates
+func rateLines(rates ...domain.Coin) string {
+	lines := make([]string, 0, len(rates))
+	for _, r := range rates {
+		lines = append(lines, botfmt.FormatRateLine(r))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDispatchDue_QuietHoursDefer(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	// 12:00 UTC = 15:00 по Москве — внутри тихих часов 14:00-16:00
+	quiet := domain.QuietHours{Start: 14 * time.Hour, End: 16 * time.Hour}
+	summary := quiet
+	summary.Summary = true
+	daily := domain.Schedule{Kind: domain.ScheduleDaily, Spec: "15:00"}
+	d.repo.EXPECT().FindDue(gomock.Any(), now).Return([]domain.Subscription{
+		{ChatID: 1, Schedule: every10, Timezone: "Europe/Moscow", Quiet: quiet},
+		{ChatID: 2, Schedule: daily, Timezone: "Europe/Moscow", Quiet: quiet},
+		{ChatID: 3, Schedule: daily, Timezone: "Europe/Moscow", Quiet: summary},
+	}, nil)
+	d.rates.EXPECT().GetLatest(gomock.Any(), "").Return(latest(now, "BTC"), nil)
+	quietEnd := time.Date(2025, 9, 16, 13, 0, 0, 0, time.UTC)
+	// интервальная — до конца тишины
+	d.repo.EXPECT().MarkMissed(gomock.Any(), int64(1), now, quietEnd).Return(nil)
+	// daily без сводки — пропуск до завтрашних 15:00
+	d.repo.EXPECT().MarkMissed(gomock.Any(), int64(2), now, now.Add(24*time.Hour)).Return(nil)
+	// daily со сводкой — сводка в конце тишины
+	d.repo.EXPECT().MarkMissed(gomock.Any(), int64(3), now, quietEnd).Return(nil)
+
+	if sent, err := svc.DispatchDue(ctx); err != nil || sent != 0 {
+		t.Fatalf("unexpected result: sent=%d err=%v", sent, err)
+	}
+}
+
+func TestDispatchDue_SummaryAfterQuietHours(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	missed := now.Add(-8 * time.Hour)
+	d.repo.EXPECT().FindDue(gomock.Any(), now).Return([]domain.Subscription{
+		{ChatID: 1, Schedule: every10, Quiet: domain.QuietHours{Start: 2 * time.Hour, End: 12 * time.Hour, Summary: true}, MissedSince: missed},
+		{ChatID: 2, Schedule: every10, Quiet: domain.QuietHours{Start: 2 * time.Hour, End: 12 * time.Hour}, MissedSince: missed},
+	}, nil)
+	prices := latest(now, "BTC")
+	d.rates.EXPECT().GetLatest(gomock.Any(), "").Return(prices, nil)
+	// цена на начало тишины — для изменения в сводке
+	was := []domain.Coin{{Symbol: "BTC", Price: decimal.NewFromInt(80), UpdatedAt: missed}}
+	d.history.EXPECT().PricesAt(gomock.Any(), "BTC", "usd", []time.Time{missed}).Return(was, nil)
+	summary := botfmt.FormatQuietSummary(missed, was, prices)
+	if !strings.Contains(summary, "+25.00%") {
+		t.Fatalf("summary must show the change since quiet hours began: %q", summary)
+	}
+	d.notifier.EXPECT().Notify(gomock.Any(), int64(1), summary+"\n\n"+rateLines(prices...)).Return(nil)
+	d.notifier.EXPECT().Notify(gomock.Any(), int64(2), rateLines(prices...)).Return(nil)
+	d.repo.EXPECT().MarkSent(gomock.Any(), gomock.Any(), now, now.Add(10*time.Minute)).Times(2).Return(nil)
+
+	if sent, err := svc.DispatchDue(ctx); err != nil || sent != 2 {
+		t.Fatalf("unexpected result: sent=%d err=%v", sent, err)
+	}
+}
+
+func TestSetQuietHours(t *testing.T) {
+	ctx, ctrl, d, svc := setupSvc(t, DispatchOptions{})
+	defer ctrl.Finish()
+
+	if err := svc.SetQuietHours(ctx, 1, domain.QuietHours{Start: 8 * time.Hour, End: 8 * time.Hour}); !errors.Is(err, derrors.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+	// нулевое значение — выключить
+	d.repo.EXPECT().SetQuietHours(gomock.Any(), int64(1), domain.QuietHours{}).Return(nil)
+	if err := svc.SetQuietHours(ctx, 1, domain.QuietHours{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// тихие часы хранятся с подпиской — без неё их некуда сохранить
+	q := domain.QuietHours{Start: 23 * time.Hour, End: 8 * time.Hour}
+	d.repo.EXPECT().SetQuietHours(gomock.Any(), int64(2), q).Return(pgx.ErrNoRows)
+	if err := svc.SetQuietHours(ctx, 2, q); !errors.Is(err, derrors.ErrSubscriptionNotFound) {
+		t.Fatalf("expected ErrSubscriptionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/botfmt"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
+	"github.com/shopspring/decimal"
+	"gopkg.in/telebot.v4"
+)
+
+// alertArgs — /alert BTC > 70000 [eur]; пробелы вокруг знака необязательны
+var alertArgs = regexp.MustCompile(`^([A-Za-z0-9]+)\s*([<>])\s*([0-9]+(?:[.,][0-9]+)?)(?:\s+([A-Za-z]+))?$`)
+
+const alertUsage = "Пример: /alert BTC > 70000 или /alert ETH < 3000 eur"
+
+// moveAlertPct — порог /movealert: 5% (в любую сторону), +5% (рост), -5% (падение)
+var moveAlertPct = regexp.MustCompile(`^([+-]?)([0-9]+(?:[.,][0-9]+)?)%$`)
+
+const moveAlertUsage = "Пример: /movealert BTC 5% 1h (+5% — только рост, -5% — только падение; можно добавить cooldown=2h и валюту)"
+
+// handleAlert — создаёт алерт на пересечение ценой уровня
+func (b *Bot) handleAlert(c telebot.Context) error {
+	chatID := c.Chat().ID
+	m := alertArgs.FindStringSubmatch(strings.TrimSpace(c.Message().Payload))
+	if m == nil {
+		return c.Send("Некорректный алерт. " + alertUsage)
+	}
+	direction := domain.AlertAbove
+	if m[2] == "<" {
+		direction = domain.AlertBelow
+	}
+	level, err := decimal.NewFromString(strings.ReplaceAll(m[3], ",", "."))
+	if err != nil {
+		return c.Send("Некорректный уровень. " + alertUsage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	a, err := b.alerts.CreateAlert(ctx, chatID, m[1], m[4], direction, level)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUnsupportedCurrency):
+			return c.Send(b.unsupportedCurrencyMsg())
+		case errors.Is(err, errs.ErrCoinNotFound):
+			tracked, _ := b.trackedSymbols(ctx)
+			return c.Send(fmt.Sprintf("Монета не поддерживается. Доступны: %s", strings.Join(tracked, ", ")))
+		case errors.Is(err, errs.ErrTooManyAlerts):
+			return c.Send("Слишком много алертов. Удалите ненужные: /alerts, /delalert {id}")
+		case errors.Is(err, errs.ErrInvalidArgument):
+			return c.Send("Некорректный уровень. " + alertUsage)
+		}
+		b.logger.Error("alerts: create failed", slog.Int64("chat_id", chatID), slog.String("error", err.Error()))
+		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+	}
+
+	msg := "Алерт создан: " + botfmt.FormatAlertLine(a)
+	if !a.Armed {
+		msg = fmt.Sprintf("Алерт #%d создан. Цена уже за уровнем — уведомлю после следующего пересечения.", a.ID)
+	}
+	return c.Send(msg)
+}
+
+// handleMoveAlert — создаёт алерт на изменение цены за окно
+func (b *Bot) handleMoveAlert(c telebot.Context) error {
+	chatID := c.Chat().ID
+	symbol, currency, direction, pct, window, cooldown, err := parseMoveAlertArgs(c.Args())
+	if err != nil {
+		return c.Send("Некорректный алерт. " + moveAlertUsage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	a, err := b.alerts.CreateMoveAlert(ctx, chatID, symbol, currency, direction, pct, window, cooldown)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUnsupportedCurrency):
+			return c.Send(b.unsupportedCurrencyMsg())
+		case errors.Is(err, errs.ErrCoinNotFound):
+			tracked, _ := b.trackedSymbols(ctx)
+			return c.Send(fmt.Sprintf("Монета не поддерживается. Доступны: %s", strings.Join(tracked, ", ")))
+		case errors.Is(err, errs.ErrTooManyAlerts):
+			return c.Send("Слишком много алертов. Удалите ненужные: /alerts, /delalert {id}")
+		case errors.Is(err, errs.ErrInvalidArgument):
+			// процент и cooldown проверены в parseMoveAlertArgs — сервис мог отвергнуть только окно
+			return c.Send("Окно должно быть от 1 минуты до 30 дней. " + moveAlertUsage)
+		}
+		b.logger.Error("alerts: create move alert failed", slog.Int64("chat_id", chatID), slog.String("error", err.Error()))
+		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+	}
+	return c.Send("Алерт создан: " + botfmt.FormatAlertLine(a))
+}
+
+// parseMoveAlertArgs — /movealert {symbol} {pct}% {window} [cooldown=2h] [currency]
+func parseMoveAlertArgs(args []string) (symbol, currency string, direction domain.AlertDirection, pct decimal.Decimal, window, cooldown time.Duration, err error) {
+	if len(args) < 3 {
+		return "", "", "", decimal.Zero, 0, 0, errors.New("expected symbol, percent and window")
+	}
+	m := moveAlertPct.FindStringSubmatch(args[1])
+	if m == nil {
+		return "", "", "", decimal.Zero, 0, 0, fmt.Errorf("invalid percent %q", args[1])
+	}
+	switch m[1] {
+	case "+":
+		direction = domain.AlertAbove
+	case "-":
+		direction = domain.AlertBelow
+	default:
+		direction = domain.AlertAny
+	}
+	if pct, err = decimal.NewFromString(strings.ReplaceAll(m[2], ",", ".")); err != nil {
+		return "", "", "", decimal.Zero, 0, 0, err
+	}
+	if !pct.IsPositive() {
+		return "", "", "", decimal.Zero, 0, 0, fmt.Errorf("percent must be positive: %q", args[1])
+	}
+	if window, err = utils.ParseWindow(args[2]); err != nil {
+		return "", "", "", decimal.Zero, 0, 0, err
+	}
+	for _, arg := range args[3:] {
+		key, value, ok := strings.Cut(arg, "=")
+		switch {
+		case !ok:
+			currency = arg
+		case strings.EqualFold(key, "cooldown"):
+			if cooldown, err = utils.ParseWindow(value); err != nil {
+				return "", "", "", decimal.Zero, 0, 0, err
+			}
+		default:
+			return "", "", "", decimal.Zero, 0, 0, fmt.Errorf("unknown option %q", key)
+		}
+	}
+	return args[0], currency, direction, pct, window, cooldown, nil
+}
+
+// handleAlerts — список алертов чата
+func (b *Bot) handleAlerts(c telebot.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	items, err := b.alerts.ListAlerts(ctx, c.Chat().ID)
+	if err != nil {
+		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+	}
+	if len(items) == 0 {
+		return c.Send("Алертов нет. " + alertUsage + "\n" + moveAlertUsage)
+	}
+	var bld strings.Builder
+	bld.WriteString("Алерты:")
+	for _, a := range items {
+		bld.WriteByte('\n')
+		bld.WriteString(botfmt.FormatAlertLine(a))
+	}
+	return c.Send(bld.String())
+}
+
+// handleDelAlert — удаляет алерт чата по id
+func (b *Bot) handleDelAlert(c telebot.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return c.Send("Укажи id алерта: /delalert 12 (список — /alerts)")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.Send("Некорректный id. Пример: /delalert 12")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := b.alerts.DeleteAlert(ctx, c.Chat().ID, id); err != nil {
+		if errors.Is(err, errs.ErrAlertNotFound) {
+			return c.Send(fmt.Sprintf("Алерт #%d не найден", id))
+		}
+		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+	}
+	return c.Send(fmt.Sprintf("Алерт #%d удалён", id))
+}
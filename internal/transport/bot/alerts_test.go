@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseMoveAlertArgs(t *testing.T) {
+	symbol, currency, direction, pct, window, cooldown, err := parseMoveAlertArgs([]string{"BTC", "-2,5%", "1h", "cooldown=2h", "eur"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if symbol != "BTC" || currency != "eur" || direction != domain.AlertBelow ||
+		!pct.Equal(decimal.RequireFromString("2.5")) || window != time.Hour || cooldown != 2*time.Hour {
+		t.Fatalf("unexpected result: %s %s %s %s %v %v", symbol, currency, direction, pct, window, cooldown)
+	}
+
+	// нулевой процент отвергается на разборе, а не сервисом — иначе бот ответит про окно
+	for _, args := range [][]string{
+		{"BTC", "0%", "1h"},
+		{"BTC", "+0.0%", "1h"},
+		{"BTC", "5", "1h"},
+		{"BTC", "5%", "week"},
+		{"BTC", "5%", "1h", "cooldown=-1h"},
+		{"BTC", "5%", "1h", "step=1"},
+		{"BTC", "5%"},
+	} {
+		if _, _, _, _, _, _, err := parseMoveAlertArgs(args); err == nil {
+			t.Errorf("parseMoveAlertArgs(%q): expected error", args)
+		}
+	}
+}
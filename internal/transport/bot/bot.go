@@ -14,16 +14,18 @@ type Bot struct {
 	bot       *telebot.Bot
 	svc       interfaces.Service
 	subs      interfaces.SubscriptionCommander
+	alerts    interfaces.AlertCommander
 	scheduler *scheduler_dispatcher.Scheduler
 	logger    *slog.Logger
 }
 
 // New создаёт новый экземпляр приложения
-func New(b *telebot.Bot, svc interfaces.Service, subs interfaces.SubscriptionCommander, logger *slog.Logger, scheduler *scheduler_dispatcher.Scheduler) (*Bot, error) {
+func New(b *telebot.Bot, svc interfaces.Service, subs interfaces.SubscriptionCommander, alerts interfaces.AlertCommander, logger *slog.Logger, scheduler *scheduler_dispatcher.Scheduler) (*Bot, error) {
 	bot := &Bot{
 		bot:       b,
 		svc:       svc,
 		subs:      subs,
+		alerts:    alerts,
 		logger:    logger,
 		scheduler: scheduler,
 	}
@@ -33,6 +35,12 @@ func New(b *telebot.Bot, svc interfaces.Service, subs interfaces.SubscriptionCom
 	b.Handle("/rates", bot.handleRates)
 	b.Handle("/startauto", bot.handleStartAuto)
 	b.Handle("/stopauto", bot.handleStopAuto)
+	b.Handle("/timezone", bot.handleTimezone)
+	b.Handle("/quiet", bot.handleQuiet)
+	b.Handle("/alert", bot.handleAlert)
+	b.Handle("/movealert", bot.handleMoveAlert)
+	b.Handle("/alerts", bot.handleAlerts)
+	b.Handle("/delalert", bot.handleDelAlert)
 	return bot, nil
 }
 
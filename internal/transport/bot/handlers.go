@@ -10,22 +10,31 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
 	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/botfmt"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
 	"gopkg.in/telebot.v4"
 )
 
-var TrackedCoins = []string{"BTC", "ETH"}
-
 var ErrInvalidInterval = errors.New("invalid interval")
 
 // handleStart — отправляет справку по доступным командам бота
 func (b *Bot) handleStart(c telebot.Context) error {
 	return c.Send("Привет! Доступные команды:\n" +
 		"/rates - цены по всем валютам\n" +
-		"/rates {symbol} - цена по конкретной валюте (BTC/ETH)\n" +
-		"/startauto {минуты} - включить автообновления\n" +
-		"/stopauto - отключить автообновления")
+		"/rates {symbol} - цена по конкретной валюте (например, BTC)\n" +
+		"/rates [symbol] {currency} - то же в другой валюте котировки (например, /rates BTC eur)\n" +
+		"/rates {symbol} window=7d change=24h - min/max и изменение за другие окна\n" +
+		"/startauto {минуты} [symbol ...] - включить автообновления (по всем монетам или только по указанным, например /startauto 10 ETH SOL)\n" +
+		"/startauto daily 09:00 [symbol ...] - каждый день в заданное время (также weekdays 18:00 или cron 0 */4 * * *)\n" +
+		"/timezone {Area/City} - часовой пояс для расписаний (например, /timezone Europe/Moscow)\n" +
+		"/quiet {с-до} [summary] - тихие часы авторассылки (например, /quiet 23:00-08:00 summary — со сводкой после), /quiet off — выключить\n" +
+		"/stopauto - отключить автообновления\n" +
+		"/alert {symbol} > {цена} [currency] - уведомить, когда цена поднимется выше уровня (или < — опустится ниже)\n" +
+		"/movealert {symbol} {N}% {окно} - уведомить об изменении цены за окно (например, /movealert BTC 5% 1h; +5% — рост, -5% — падение, cooldown=2h — пауза)\n" +
+		"/alerts - список алертов\n" +
+		"/delalert {id} - удалить алерт")
 }
 
 // handleRates — выводит курсы: без аргументов — все монеты, с аргументом символа — подробности по одной
@@ -34,9 +43,23 @@ func (b *Bot) handleRates(c telebot.Context) error {
 	defer cancel()
 
 	args := c.Args()
+	tracked, err := b.trackedSymbols(ctx)
+	if err != nil {
+		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+	}
+
+	// /rates eur — все монеты в валюте котировки (символ монеты важнее: /rates BTC — это монета)
+	var currency string
+	if len(args) == 1 && !slices.Contains(tracked, strings.ToUpper(args[0])) && b.isCurrency(args[0]) {
+		currency, args = args[0], nil
+	}
+
 	if len(args) == 0 {
-		list, err := b.svc.GetLatest(ctx)
+		list, err := b.svc.GetLatest(ctx, currency)
 		if err != nil {
+			if errors.Is(err, errs.ErrUnsupportedCurrency) {
+				return c.Send(b.unsupportedCurrencyMsg())
+			}
 			if errors.Is(err, errs.ErrPriceNotFound) {
 				return c.Send("Данные о цене не найдены")
 			}
@@ -53,17 +76,26 @@ func (b *Bot) handleRates(c telebot.Context) error {
 		return c.Send(bld.String())
 	}
 
-	symbol := args[0]
-	symbol = strings.ToUpper(symbol)
-	if !slices.Contains(TrackedCoins, symbol) {
-		return c.Send(fmt.Sprintf("Монета не поддерживается. Доступны: %s", strings.Join(TrackedCoins, ", ")))
+	symbol := strings.ToUpper(args[0])
+	if !slices.Contains(tracked, symbol) {
+		return c.Send(fmt.Sprintf("Монета не поддерживается. Доступны: %s", strings.Join(tracked, ", ")))
+	}
+	opts, argCurrency, err := parseStatsArgs(args[1:])
+	if err != nil {
+		return c.Send("Некорректное окно. Пример: /rates BTC window=7d change=24h")
+	}
+	if argCurrency != "" {
+		currency = argCurrency
 	}
-	now := time.Now().UTC()
-	from := now.Add(-24 * time.Hour)
-	to := now
 
-	latest, minV, maxV, pct, err := b.svc.GetLatestBySymbol(ctx, symbol, from, to)
+	stats, err := b.svc.GetStats(ctx, symbol, currency, opts)
 	if err != nil {
+		if errors.Is(err, errs.ErrUnsupportedCurrency) {
+			return c.Send(b.unsupportedCurrencyMsg())
+		}
+		if errors.Is(err, errs.ErrInvalidArgument) {
+			return c.Send("Окно должно быть от 1 минуты до 90 дней. Пример: /rates BTC window=7d change=24h")
+		}
 		if errors.Is(err, errs.ErrCoinNotFound) {
 			return c.Send("Валюта не найдена")
 		}
@@ -72,10 +104,15 @@ func (b *Bot) handleRates(c telebot.Context) error {
 		}
 		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
 	}
-	return c.Send(botfmt.FormatRateDetails(latest, minV, maxV, pct))
+	return c.Send(botfmt.FormatRateDetails(stats))
 }
 
-// handleStartAuto — включает авторассылку курсов для чата с указанным интервалом в минутах
+// handleStartAuto — включает авторассылку курсов для чата по расписанию; монеты после расписания — только по ним:
+//
+//	/startauto 10 [ETH SOL]          — каждые 10 минут
+//	/startauto daily 09:00 [ETH]     — каждый день в 09:00 по часовому поясу чата
+//	/startauto weekdays 18:00        — по будням в 18:00
+//	/startauto cron 0 */4 * * * [BTC] — cron-выражение из 5 полей
 func (b *Bot) handleStartAuto(c telebot.Context) error {
 	b.logger.Debug("subscription: /startauto received",
 		slog.Int64("chat_id", c.Chat().ID),
@@ -85,34 +122,51 @@ func (b *Bot) handleStartAuto(c telebot.Context) error {
 
 	args := c.Args()
 	chatID := c.Chat().ID
-	if len(args) != 1 {
+	if len(args) < 1 {
 		b.logger.Warn("subscription: /startauto wrong args",
 			slog.Int64("chat_id", chatID),
 			slog.Int("args_len", len(args)),
 			slog.String("text", c.Text()),
 		)
-		return c.Send("Укажи интервал в минутах: /startauto 10")
+		return c.Send(startAutoUsage)
 	}
-	mins, err := parseMinutes(args[0])
+	sched, coins, err := parseSchedule(args)
 	if err != nil {
-		b.logger.Warn("subscription: /startauto invalid interval",
+		b.logger.Warn("subscription: /startauto invalid schedule",
 			slog.Int64("chat_id", chatID),
-			slog.String("arg", args[0]),
+			slog.String("text", c.Text()),
 		)
-		return c.Send("Некорректный интервал. Пример: /startauto 10")
+		return c.Send("Некорректное расписание.\n" + startAutoUsage)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if err := b.subs.Enable(ctx, chatID, mins); err != nil {
+	sub, err := b.subs.Enable(ctx, chatID, sched, coins)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrCoinNotFound):
+			tracked, _ := b.trackedSymbols(ctx)
+			return c.Send(fmt.Sprintf("Монета не поддерживается. Доступны: %s", strings.Join(tracked, ", ")))
+		case errors.Is(err, errs.ErrInvalidArgument):
+			return c.Send("Некорректное расписание.\n" + startAutoUsage)
+		}
 		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
 	}
 	b.logger.Debug("subscription: startauto enabled",
 		slog.Int64("chat_id", chatID),
-		slog.Int("interval_min", mins),
+		slog.String("schedule", string(sched.Kind)),
+		slog.Any("coins", sub.Coins),
 	)
-	if err := c.Send(fmt.Sprintf("Автообновления включены! (каждые %d мин.) ", mins)); err != nil {
+	coinsText := "все монеты"
+	if len(sub.Coins) > 0 {
+		coinsText = strings.Join(sub.Coins, ", ")
+	}
+	msg := fmt.Sprintf("Автообновления включены! (%s: %s)", botfmt.FormatSchedule(sub.Schedule), coinsText)
+	if sched.Kind != domain.ScheduleInterval {
+		msg += fmt.Sprintf("\nБлижайшая рассылка: %s (%s)", inTimezone(sub.NextRun, sub.Timezone).Format("02.01 15:04"), sub.Timezone)
+	}
+	if err := c.Send(msg); err != nil {
 		b.logger.Error("subscription: /startauto confirm send failed",
 			slog.Int64("chat_id", chatID),
 			slog.String("error", err.Error()),
@@ -122,6 +176,77 @@ func (b *Bot) handleStartAuto(c telebot.Context) error {
 	return nil
 }
 
+// handleTimezone — /timezone Europe/Moscow задаёт часовой пояс чата для расписаний daily/cron; без аргумента — показывает текущий
+func (b *Bot) handleTimezone(c telebot.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	chatID := c.Chat().ID
+	args := c.Args()
+	if len(args) == 0 {
+		tz, err := b.subs.Timezone(ctx, chatID)
+		if err != nil {
+			return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+		}
+		return c.Send(fmt.Sprintf("Часовой пояс чата: %s. Сменить: /timezone Europe/Moscow", tz))
+	}
+	if err := b.subs.SetTimezone(ctx, chatID, args[0]); err != nil {
+		if errors.Is(err, errs.ErrInvalidArgument) {
+			return c.Send("Неизвестный часовой пояс. Пример: /timezone Europe/Moscow")
+		}
+		b.logger.Error("subscription: /timezone failed",
+			slog.Int64("chat_id", chatID),
+			slog.String("error", err.Error()),
+		)
+		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+	}
+	return c.Send(fmt.Sprintf("Часовой пояс установлен: %s", args[0]))
+}
+
+// handleQuiet — тихие часы чата: /quiet 23:00-08:00 [summary] — не присылать рассылки ночью
+// (summary — одна сводка после окончания), /quiet off — выключить, без аргументов — показать текущие
+func (b *Bot) handleQuiet(c telebot.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	chatID := c.Chat().ID
+	args := c.Args()
+	if len(args) == 0 {
+		q, err := b.subs.QuietHours(ctx, chatID)
+		if err != nil {
+			return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+		}
+		if !q.Enabled() {
+			return c.Send("Тихие часы не заданы. Пример: /quiet 23:00-08:00 summary")
+		}
+		return c.Send(fmt.Sprintf("Тихие часы: %s. Выключить: /quiet off", botfmt.FormatQuietHours(q)))
+	}
+	var q domain.QuietHours
+	if !strings.EqualFold(args[0], "off") {
+		var err error
+		if q, err = parseQuietHours(args); err != nil {
+			return c.Send("Некорректные тихие часы. Пример: /quiet 23:00-08:00 или /quiet 23:00-08:00 summary (со сводкой пропущенного)")
+		}
+	}
+	if err := b.subs.SetQuietHours(ctx, chatID, q); err != nil {
+		if errors.Is(err, errs.ErrInvalidArgument) {
+			return c.Send("Некорректные тихие часы: начало должно отличаться от конца. Пример: /quiet 23:00-08:00")
+		}
+		if errors.Is(err, errs.ErrSubscriptionNotFound) {
+			return c.Send("Тихие часы задаются для авторассылки — сначала включите её: /startauto")
+		}
+		b.logger.Error("subscription: /quiet failed",
+			slog.Int64("chat_id", chatID),
+			slog.String("error", err.Error()),
+		)
+		return c.Send("Внутренняя ошибка сервиса, попробуйте позже")
+	}
+	if !q.Enabled() {
+		return c.Send("Тихие часы выключены")
+	}
+	return c.Send(fmt.Sprintf("Тихие часы установлены: %s (по часовому поясу чата, /timezone)", botfmt.FormatQuietHours(q)))
+}
+
 // handleStopAuto — отключает авторассылку курсов для текущего чата
 func (b *Bot) handleStopAuto(c telebot.Context) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -133,6 +258,125 @@ func (b *Bot) handleStopAuto(c telebot.Context) error {
 	return c.Send("Автообновления отключены!")
 }
 
+// trackedSymbols — символы включённых монет из реестра
+func (b *Bot) trackedSymbols(ctx context.Context) ([]string, error) {
+	coins, err := b.svc.TrackedCoins(ctx)
+	if err != nil {
+		b.logger.Error("bot: tracked coins failed", slog.String("error", err.Error()))
+		return nil, err
+	}
+	out := make([]string, 0, len(coins))
+	for _, c := range coins {
+		out = append(out, c.Symbol)
+	}
+	return out, nil
+}
+
+// isCurrency — поддерживается ли валюта котировки
+func (b *Bot) isCurrency(s string) bool {
+	return slices.Contains(b.svc.Currencies(), strings.ToLower(s))
+}
+
+// unsupportedCurrencyMsg — ответ на неизвестную валюту котировки
+func (b *Bot) unsupportedCurrencyMsg() string {
+	return fmt.Sprintf("Валюта котировки не поддерживается. Доступны: %s", strings.Join(b.svc.Currencies(), ", "))
+}
+
+// parseStatsArgs — аргументы /rates после символа: валюта котировки, window=7d, change=24h (в любом порядке)
+func parseStatsArgs(args []string) (opts domain.StatsOptions, currency string, err error) {
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			currency = arg
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "window":
+			opts.Window, err = utils.ParseWindow(value)
+		case "change":
+			opts.Change, err = utils.ParseWindow(value)
+		default:
+			err = fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return domain.StatsOptions{}, "", err
+		}
+	}
+	return opts, currency, nil
+}
+
+const startAutoUsage = "Укажи расписание и, при желании, монеты:\n" +
+	"/startauto 10 — каждые 10 минут\n" +
+	"/startauto daily 09:00 ETH SOL — каждый день в 09:00\n" +
+	"/startauto weekdays 18:00 — по будням в 18:00\n" +
+	"/startauto cron 0 */4 * * * — по cron-выражению\n" +
+	"Время — по часовому поясу чата (/timezone)"
+
+// parseSchedule — расписание /startauto и монеты после него
+func parseSchedule(args []string) (domain.Schedule, []string, error) {
+	switch strings.ToLower(args[0]) {
+	case "daily", "weekdays":
+		if len(args) < 2 {
+			return domain.Schedule{}, nil, ErrInvalidInterval
+		}
+		at, err := time.Parse("15:04", args[1])
+		if err != nil {
+			return domain.Schedule{}, nil, ErrInvalidInterval
+		}
+		if strings.EqualFold(args[0], "weekdays") {
+			spec := fmt.Sprintf("%d %d * * 1-5", at.Minute(), at.Hour())
+			return domain.Schedule{Kind: domain.ScheduleCron, Spec: spec}, args[2:], nil
+		}
+		return domain.Schedule{Kind: domain.ScheduleDaily, Spec: at.Format("15:04")}, args[2:], nil
+	case "cron":
+		if len(args) < 6 {
+			return domain.Schedule{}, nil, ErrInvalidInterval
+		}
+		return domain.Schedule{Kind: domain.ScheduleCron, Spec: strings.Join(args[1:6], " ")}, args[6:], nil
+	}
+	mins, err := parseMinutes(args[0])
+	if err != nil {
+		return domain.Schedule{}, nil, err
+	}
+	return domain.Schedule{Kind: domain.ScheduleInterval, Interval: time.Duration(mins) * time.Minute}, args[1:], nil
+}
+
+// parseQuietHours — аргументы /quiet: «23:00-08:00» и необязательный «summary»
+func parseQuietHours(args []string) (domain.QuietHours, error) {
+	from, to, ok := strings.Cut(args[0], "-")
+	if !ok || len(args) > 2 {
+		return domain.QuietHours{}, ErrInvalidInterval
+	}
+	start, err := time.Parse("15:04", from)
+	if err != nil {
+		return domain.QuietHours{}, ErrInvalidInterval
+	}
+	end, err := time.Parse("15:04", to)
+	if err != nil {
+		return domain.QuietHours{}, ErrInvalidInterval
+	}
+	q := domain.QuietHours{
+		Start: time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		End:   time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+	}
+	if len(args) == 2 {
+		if !strings.EqualFold(args[1], "summary") {
+			return domain.QuietHours{}, ErrInvalidInterval
+		}
+		q.Summary = true
+	}
+	return q, nil
+}
+
+// inTimezone — t по часовому поясу чата (неизвестный — UTC)
+func inTimezone(t time.Time, tz string) time.Time {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return t.UTC()
+	}
+	return t.In(loc)
+}
+
 // parseMinutes — парсит строку с минутами и валидирует значение (> 0)
 func parseMinutes(s string) (int, error) {
 	s = strings.TrimSpace(s)
@@ -0,0 +1,22 @@
+package bot
+
+import (
+	"context"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Notifier — отправка уведомлений в чаты (алерты) через Telegram
+type Notifier struct {
+	bot *telebot.Bot
+}
+
+func NewNotifier(b *telebot.Bot) *Notifier {
+	return &Notifier{bot: b}
+}
+
+// Notify — отправить текст в чат chatID
+func (n *Notifier) Notify(_ context.Context, chatID int64, text string) error {
+	_, err := n.bot.Send(&telebot.Chat{ID: chatID}, text)
+	return err
+}
@@ -0,0 +1,214 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"expvar"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"github.com/labstack/echo/v4"
+)
+
+type APICoin struct {
+	ProviderID string `json:"provider_id"`
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+}
+
+// AddCoinRequest — тело POST /admin/coins
+type AddCoinRequest struct {
+	ProviderID string `json:"provider_id"`
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+}
+
+// UpdateCoinRequest — тело PATCH /admin/coins/{symbol}; отсутствующие поля не меняются
+type UpdateCoinRequest struct {
+	Enabled *bool   `json:"enabled"`
+	Name    *string `json:"name"`
+}
+
+func ToAPICoin(c domain.CoinInfo) APICoin {
+	return APICoin{
+		ProviderID: c.ProviderID,
+		Symbol:     c.Symbol,
+		Name:       c.Name,
+		Enabled:    c.Enabled,
+	}
+}
+
+// AdminHandler — HTTP‑handler для управления реестром монет.
+type AdminHandler struct {
+	logger  *slog.Logger
+	svc     interfaces.CoinAdmin
+	token   string
+	timeout time.Duration
+}
+
+func NewAdminHandler(logger *slog.Logger, svc interfaces.CoinAdmin, token string, timeout time.Duration) *AdminHandler {
+	if logger == nil {
+		log.Fatal("nil logger")
+	}
+	if svc == nil {
+		log.Fatal("nil service")
+	}
+	// Проверка монеты в каталоге провайдера может быть долгой
+	if timeout <= 0 {
+		timeout = time.Second * 10
+	}
+	return &AdminHandler{
+		logger:  logger,
+		svc:     svc,
+		token:   token,
+		timeout: timeout,
+	}
+}
+
+func (h *AdminHandler) RegisterRoutes(r interface {
+	Group(prefix string, m ...echo.MiddlewareFunc) *echo.Group
+}) {
+	g := r.Group("/admin", h.authMiddleware)
+	g.GET("/coins", h.ListCoins)
+	g.POST("/coins", h.AddCoin)
+	g.PATCH("/coins/:symbol", h.UpdateCoin)
+	g.DELETE("/coins/:symbol", h.DeleteCoin)
+
+	// Метрики процесса (expvar): внутренние счётчики и memstats — только с токеном администратора
+	r.Group("/debug", h.authMiddleware).GET("/vars", echo.WrapHandler(expvar.Handler()))
+}
+
+// authMiddleware — проверка заголовка Authorization: Bearer <admin token>
+func (h *AdminHandler) authMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		got, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) != 1 {
+			return c.JSON(http.StatusUnauthorized, echo.Map{
+				"error": "unauthorized",
+			})
+		}
+		return next(c)
+	}
+}
+
+func (h *AdminHandler) ListCoins(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	items, err := h.svc.ListCoins(ctx)
+	if err != nil {
+		return h.internalError(c, "ListCoins", "", err)
+	}
+
+	out := make([]APICoin, 0, len(items))
+	for _, item := range items {
+		out = append(out, ToAPICoin(item))
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+func (h *AdminHandler) AddCoin(c echo.Context) error {
+	var req AddCoinRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"error": "invalid_body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	coin, err := h.svc.AddCoin(ctx, req.ProviderID, req.Symbol, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrInvalidArgument):
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"error": "provider_id_required",
+			})
+		case errors.Is(err, errs.ErrUnknownProviderID):
+			return c.JSON(http.StatusUnprocessableEntity, echo.Map{
+				"error":       "unknown_provider_id",
+				"provider_id": req.ProviderID,
+			})
+		case errors.Is(err, errs.ErrCoinAlreadyExists):
+			return c.JSON(http.StatusConflict, echo.Map{
+				"error":       "coin_already_exists",
+				"provider_id": req.ProviderID,
+			})
+		case errors.Is(err, errs.ErrRateLimited):
+			return c.JSON(http.StatusServiceUnavailable, echo.Map{
+				"error": "provider_rate_limited",
+			})
+		}
+		return h.internalError(c, "AddCoin", req.ProviderID, err)
+	}
+	return c.JSON(http.StatusCreated, ToAPICoin(coin))
+}
+
+func (h *AdminHandler) UpdateCoin(c echo.Context) error {
+	symbol := strings.ToUpper(strings.TrimSpace(c.Param("symbol")))
+
+	var req UpdateCoinRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"error": "invalid_body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	coin, err := h.svc.UpdateCoin(ctx, symbol, req.Enabled, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrInvalidArgument):
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"error": "nothing_to_update",
+			})
+		case errors.Is(err, errs.ErrCoinNotFound):
+			return c.JSON(http.StatusNotFound, echo.Map{
+				"error":  "coin_not_found",
+				"symbol": symbol,
+			})
+		}
+		return h.internalError(c, "UpdateCoin", symbol, err)
+	}
+	return c.JSON(http.StatusOK, ToAPICoin(coin))
+}
+
+func (h *AdminHandler) DeleteCoin(c echo.Context) error {
+	symbol := strings.ToUpper(strings.TrimSpace(c.Param("symbol")))
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	if err := h.svc.RemoveCoin(ctx, symbol); err != nil {
+		if errors.Is(err, errs.ErrCoinNotFound) {
+			return c.JSON(http.StatusNotFound, echo.Map{
+				"error":  "coin_not_found",
+				"symbol": symbol,
+			})
+		}
+		return h.internalError(c, "DeleteCoin", symbol, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *AdminHandler) internalError(c echo.Context, op, subject string, err error) error {
+	h.logger.Error(op+" failed",
+		slog.String("op", op),
+		slog.String("subject", subject),
+		slog.String("error", err.Error()),
+	)
+	return c.JSON(http.StatusInternalServerError, echo.Map{
+		"error": "internal_server_error",
+	})
+}
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/labstack/echo/v4"
+)
+
+// APICandle — OHLC-свеча; time — начало интервала
+type APICandle struct {
+	Time   time.Time   `json:"time"`
+	Open   json.Number `json:"open"`
+	High   json.Number `json:"high"`
+	Low    json.Number `json:"low"`
+	Close  json.Number `json:"close"`
+	Points int         `json:"points"`
+}
+
+// APICandles — ответ GET /rates/{symbol}/candles
+type APICandles struct {
+	Symbol   string      `json:"symbol"`
+	Currency string      `json:"currency"`
+	Interval string      `json:"interval"`
+	Candles  []APICandle `json:"candles"`
+}
+
+func ToAPICandle(c domain.Candle) APICandle {
+	return APICandle{
+		Time:   c.Time,
+		Open:   number(c.Open),
+		High:   number(c.High),
+		Low:    number(c.Low),
+		Close:  number(c.Close),
+		Points: c.Points,
+	}
+}
+
+// GetCandles — GET /rates/{symbol}/candles?interval=1h&from=&to=&currency=
+func (h *RatesHandler) GetCandles(c echo.Context) error {
+	symbol := strings.ToUpper(strings.TrimSpace(c.Param("symbol")))
+	interval := strings.ToLower(strings.TrimSpace(c.QueryParam("interval")))
+	if interval == "" {
+		interval = "1h"
+	}
+	currency := strings.ToLower(strings.TrimSpace(c.QueryParam("currency")))
+
+	from, err := parseTimeParam(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid_time_range", "param": "from"})
+	}
+	to, err := parseTimeParam(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid_time_range", "param": "to"})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	candles, err := h.svc.Candles(ctx, symbol, currency, interval, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUnsupportedCurrency):
+			return h.unsupportedCurrency(c, currency)
+		case errors.Is(err, errs.ErrInvalidArgument):
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"error":   "invalid_argument",
+				"message": err.Error(),
+			})
+		case errors.Is(err, errs.ErrCoinNotFound):
+			return c.JSON(http.StatusNotFound, echo.Map{
+				"error":  "coin_not_found",
+				"symbol": symbol,
+			})
+		}
+		h.logger.Error("GetCandles failed",
+			slog.String("op", "GetCandles"),
+			slog.String("symbol", symbol),
+			slog.String("error", err.Error()),
+		)
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"error": "internal_server_error",
+		})
+	}
+
+	if currency == "" {
+		currency = h.svc.Currencies()[0]
+	}
+	out := APICandles{
+		Symbol:   symbol,
+		Currency: currency,
+		Interval: interval,
+		Candles:  make([]APICandle, 0, len(candles)),
+	}
+	for _, cd := range candles {
+		out.Candles = append(out.Candles, ToAPICandle(cd))
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// parseTimeParam — RFC3339 или unix-время в секундах; пустая строка — нулевое время
+func parseTimeParam(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", s, err)
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
+	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
+	"github.com/labstack/echo/v4"
+)
+
+// APIPricePoint — одна сохранённая цена
+type APIPricePoint struct {
+	Time   time.Time   `json:"time"`
+	Price  json.Number `json:"price"`
+	Source string      `json:"source"`
+}
+
+// APIHistory — ответ GET /rates/{symbol}/history.
+// NextCursor пуст на последней странице.
+type APIHistory struct {
+	Symbol     string          `json:"symbol"`
+	Currency   string          `json:"currency"`
+	Prices     []APIPricePoint `json:"prices"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func ToAPIPricePoint(c domain.Coin) APIPricePoint {
+	return APIPricePoint{
+		Time:   c.UpdatedAt,
+		Price:  number(c.Price),
+		Source: c.Source,
+	}
+}
+
+// GetHistory — GET /rates/{symbol}/history?from=&to=&limit=&cursor=&currency=
+func (h *RatesHandler) GetHistory(c echo.Context) error {
+	symbol := strings.ToUpper(strings.TrimSpace(c.Param("symbol")))
+	currency := strings.ToLower(strings.TrimSpace(c.QueryParam("currency")))
+
+	from, err := parseTimeParam(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid_time_range", "param": "from"})
+	}
+	to, err := parseTimeParam(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid_time_range", "param": "to"})
+	}
+	var limit int
+	if s := strings.TrimSpace(c.QueryParam("limit")); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit <= 0 {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid_argument", "param": "limit"})
+		}
+	}
+	after, err := decodeCursor(c.QueryParam("cursor"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid_cursor", "param": "cursor"})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	items, next, err := h.svc.HistoryPage(ctx, symbol, currency, from, to, after, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUnsupportedCurrency):
+			return h.unsupportedCurrency(c, currency)
+		case errors.Is(err, errs.ErrInvalidArgument):
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"error":   "invalid_argument",
+				"message": err.Error(),
+			})
+		case errors.Is(err, errs.ErrCoinNotFound):
+			return c.JSON(http.StatusNotFound, echo.Map{
+				"error":  "coin_not_found",
+				"symbol": symbol,
+			})
+		}
+		h.logger.Error("GetHistory failed",
+			slog.String("op", "GetHistory"),
+			slog.String("symbol", symbol),
+			slog.String("error", err.Error()),
+		)
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"error": "internal_server_error",
+		})
+	}
+
+	if currency == "" {
+		currency = h.svc.Currencies()[0]
+	}
+	out := APIHistory{
+		Symbol:     symbol,
+		Currency:   currency,
+		Prices:     make([]APIPricePoint, 0, len(items)),
+		NextCursor: encodeCursor(next),
+	}
+	for _, item := range items {
+		out.Prices = append(out.Prices, ToAPIPricePoint(item))
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// encodeCursor — непрозрачный курсор "<unix-нс>:<source>" последней цены страницы; нулевой — пустой курсор
+func encodeCursor(c domain.HistoryCursor) string {
+	if c.IsZero() {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.Time.UnixNano(), 10) + ":" + c.Source))
+}
+
+func decodeCursor(s string) (domain.HistoryCursor, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return domain.HistoryCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.HistoryCursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	ts, source, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return domain.HistoryCursor{}, errors.New("invalid cursor: missing source")
+	}
+	ns, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return domain.HistoryCursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return domain.HistoryCursor{Time: time.Unix(0, ns).UTC(), Source: source}, nil
+}
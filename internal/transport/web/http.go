@@ -2,6 +2,7 @@ package web
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
@@ -13,36 +14,97 @@ import (
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/domain"
 	errs "github.com/NastyaGoryachaya/crypto-rate-service/internal/errors"
 	"github.com/NastyaGoryachaya/crypto-rate-service/internal/interfaces"
+	"github.com/NastyaGoryachaya/crypto-rate-service/internal/pkg/utils"
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
 )
 
+// APIRate — цены отдаются JSON-числами в точной десятичной записи (json.Number),
+// без округления через float64.
 type APIRate struct {
-	Symbol      string    `json:"symbol"`
-	Price       float64   `json:"price"`
-	Min24h      *float64  `json:"min_24h,omitempty"`
-	Max24h      *float64  `json:"max_24h,omitempty"`
-	Change1hPct *float64  `json:"change_1h_pct,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Symbol      string       `json:"symbol"`
+	Currency    string       `json:"currency"`
+	Price       json.Number  `json:"price"`
+	Min24h      *json.Number `json:"min_24h,omitempty"`
+	Max24h      *json.Number `json:"max_24h,omitempty"`
+	Change1hPct *float64     `json:"change_1h_pct,omitempty"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+
+	// Рыночные показатели провайдера (если есть)
+	Volume24h         *float64     `json:"volume_24h,omitempty"`
+	MarketCap         *float64     `json:"market_cap,omitempty"`
+	High24h           *json.Number `json:"high_24h,omitempty"`
+	Low24h            *json.Number `json:"low_24h,omitempty"`
+	Change24hPct      *float64     `json:"change_24h_pct,omitempty"`
+	CirculatingSupply *float64     `json:"circulating_supply,omitempty"`
+
+	// Статистика по окнам (только для /rates/{symbol})
+	Window  string       `json:"window,omitempty"`
+	Min     *json.Number `json:"min,omitempty"`
+	Max     *json.Number `json:"max,omitempty"`
+	Change  *APIChange   `json:"change,omitempty"`
+	Changes []APIChange  `json:"changes,omitempty"`
+}
+
+// APIChange — изменение цены за окно; pct = null — нет цены на начало окна
+type APIChange struct {
+	Window string   `json:"window"`
+	Pct    *float64 `json:"pct"`
+}
+
+func ToAPIChange(ch domain.Change) APIChange {
+	return APIChange{Window: utils.FormatWindow(ch.Window), Pct: ch.Pct}
 }
 
 // ToAPI — локальный конвертер транспорта
 func ToAPI(item domain.Coin) APIRate {
 	return APIRate{
-		Symbol:    item.Symbol,
-		Price:     item.Price,
-		UpdatedAt: item.UpdatedAt,
+		Symbol:            item.Symbol,
+		Currency:          item.Currency,
+		Price:             number(item.Price),
+		UpdatedAt:         item.UpdatedAt,
+		Volume24h:         item.Market.Volume24h,
+		MarketCap:         item.Market.MarketCap,
+		High24h:           numberPtr(item.Market.High24h),
+		Low24h:            numberPtr(item.Market.Low24h),
+		Change24hPct:      item.Market.Change24hPct,
+		CirculatingSupply: item.Market.CirculatingSupply,
 	}
 }
 
-func ToAPIWithStats(latest domain.Coin, min, max, pct float64) APIRate {
-	return APIRate{
-		Symbol:      latest.Symbol,
-		Price:       latest.Price,
-		Min24h:      &min,
-		Max24h:      &max,
-		Change1hPct: &pct,
-		UpdatedAt:   latest.UpdatedAt,
+// ToAPIWithStats — цена со статистикой. Поля min_24h/max_24h/change_1h_pct сохранены
+// для старых клиентов и заполняются, когда данные за эти окна есть в статистике.
+func ToAPIWithStats(st domain.RateStats) APIRate {
+	out := ToAPI(st.Latest)
+	minN, maxN := number(st.Min), number(st.Max)
+	out.Window = utils.FormatWindow(st.Window)
+	out.Min, out.Max = &minN, &maxN
+	change := ToAPIChange(st.Change)
+	out.Change = &change
+	out.Changes = make([]APIChange, 0, len(st.Changes))
+	for _, ch := range st.Changes {
+		out.Changes = append(out.Changes, ToAPIChange(ch))
+		if ch.Window == time.Hour {
+			out.Change1hPct = ch.Pct
+		}
+	}
+	if st.Window == 24*time.Hour {
+		out.Min24h, out.Max24h = &minN, &maxN
 	}
+	return out
+}
+
+// number — точная десятичная запись для JSON (без экспоненты)
+func number(d decimal.Decimal) json.Number {
+	return json.Number(d.String())
+}
+
+func numberPtr(d *decimal.Decimal) *json.Number {
+	if d == nil {
+		return nil
+	}
+	n := number(*d)
+	return &n
 }
 
 // RatesHandler — HTTP‑handler для курсов.
@@ -76,18 +138,24 @@ func (h *RatesHandler) RegisterRoutes(r interface {
 	// Регистрируем маршруты
 	r.GET("/rates", h.GetRates)
 	r.GET("/rates/:symbol", h.GetRateBySymbol)
+	r.GET("/rates/:symbol/candles", h.GetCandles)
+	r.GET("/rates/:symbol/history", h.GetHistory)
 }
 
 func (h *RatesHandler) GetRates(c echo.Context) error {
 	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
 	defer cancel()
 
-	items, err := h.svc.GetLatest(ctx)
+	currency := strings.ToLower(strings.TrimSpace(c.QueryParam("currency")))
+	items, err := h.svc.GetLatest(ctx, currency)
 	// Обрабатываем ошибки сервиса и возвращаем JSON
 	if err == nil && len(items) == 0 {
 		return c.JSON(http.StatusOK, []APIRate{})
 	}
 	if err != nil {
+		if errors.Is(err, errs.ErrUnsupportedCurrency) {
+			return h.unsupportedCurrency(c, currency)
+		}
 		if errors.Is(err, errs.ErrPriceNotFound) {
 			return c.JSON(http.StatusNotFound, echo.Map{
 				"error": "prices_not_found",
@@ -121,11 +189,29 @@ func (h *RatesHandler) GetRateBySymbol(c echo.Context) error {
 	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
 	defer cancel()
 
-	now := time.Now().UTC()
-	from := now.Add(-24 * time.Hour)
+	currency := strings.ToLower(strings.TrimSpace(c.QueryParam("currency")))
+	var (
+		opts domain.StatsOptions
+		err  error
+	)
+	if opts.Window, err = windowParam(c, "window"); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid_argument", "param": "window"})
+	}
+	if opts.Change, err = windowParam(c, "change"); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid_argument", "param": "change"})
+	}
 
-	latest, minV, maxV, pct, err := h.svc.GetLatestBySymbol(ctx, symbol, from, now)
+	stats, err := h.svc.GetStats(ctx, symbol, currency, opts)
 	if err != nil {
+		if errors.Is(err, errs.ErrUnsupportedCurrency) {
+			return h.unsupportedCurrency(c, currency)
+		}
+		if errors.Is(err, errs.ErrInvalidArgument) {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"error":   "invalid_argument",
+				"message": err.Error(),
+			})
+		}
 		if errors.Is(err, errs.ErrCoinNotFound) {
 			return c.JSON(http.StatusNotFound, echo.Map{
 				"error":  "coin_not_found",
@@ -147,6 +233,24 @@ func (h *RatesHandler) GetRateBySymbol(c echo.Context) error {
 		})
 	}
 
-	out := ToAPIWithStats(latest, minV, maxV, pct)
+	out := ToAPIWithStats(stats)
 	return c.JSON(http.StatusOK, out)
 }
+
+// windowParam — окно из query-параметра (7d, 24h, 15m); пустой параметр — 0 (окно по умолчанию)
+func windowParam(c echo.Context, name string) (time.Duration, error) {
+	v := strings.TrimSpace(c.QueryParam(name))
+	if v == "" {
+		return 0, nil
+	}
+	return utils.ParseWindow(v)
+}
+
+// unsupportedCurrency — 400 с перечнем доступных валют котировки
+func (h *RatesHandler) unsupportedCurrency(c echo.Context, currency string) error {
+	return c.JSON(http.StatusBadRequest, echo.Map{
+		"error":      "unsupported_currency",
+		"currency":   currency,
+		"currencies": h.svc.Currencies(),
+	})
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS uq_coins_provider_id;
+
+ALTER TABLE coins
+    DROP COLUMN IF EXISTS enabled,
+    DROP COLUMN IF EXISTS provider_id;
\ No newline at end of file
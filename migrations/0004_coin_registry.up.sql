@@ -0,0 +1,16 @@
+-- Реестр монет: идентификатор у провайдера + флаг включения
+ALTER TABLE coins
+    ADD COLUMN IF NOT EXISTS provider_id TEXT,
+    ADD COLUMN IF NOT EXISTS enabled     BOOLEAN NOT NULL DEFAULT TRUE;
+
+UPDATE coins SET provider_id = 'bitcoin'  WHERE symbol = 'BTC' AND provider_id IS NULL;
+UPDATE coins SET provider_id = 'ethereum' WHERE symbol = 'ETH' AND provider_id IS NULL;
+UPDATE coins SET provider_id = lower(symbol) WHERE provider_id IS NULL;
+
+ALTER TABLE coins ALTER COLUMN provider_id SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_coins_provider_id
+    ON coins (provider_id);
+
+COMMENT ON COLUMN coins.provider_id IS 'Идентификатор монеты у провайдера (bitcoin, ethereum)';
+COMMENT ON COLUMN coins.enabled     IS 'Отслеживается ли монета (участвует в загрузке курсов)';
\ No newline at end of file
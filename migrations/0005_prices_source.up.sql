@@ -0,0 +1,5 @@
+-- Источник цены (какой провайдер её вернул)
+ALTER TABLE prices
+    ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN prices.source IS 'Провайдер, от которого получена цена (coingecko, binance)';
\ No newline at end of file
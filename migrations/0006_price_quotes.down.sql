@@ -0,0 +1,4 @@
+ALTER TABLE prices
+    DROP COLUMN IF EXISTS spread_pct;
+
+DROP TABLE IF EXISTS price_quotes;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- Котировки провайдеров, из которых посчитана консенсус-цена
+CREATE TABLE IF NOT EXISTS price_quotes (
+    coin_symbol TEXT NOT NULL REFERENCES coins(symbol) ON DELETE CASCADE,
+    timestamp   TIMESTAMPTZ NOT NULL,
+    source      TEXT NOT NULL,
+    value       NUMERIC(20,10) NOT NULL,
+    rejected    BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (coin_symbol, timestamp, source)
+);
+
+COMMENT ON TABLE price_quotes IS 'Котировки отдельных провайдеров для агрегированных цен';
+COMMENT ON COLUMN price_quotes.rejected IS 'Котировка отброшена как выброс';
+
+-- Разброс котировок между провайдерами
+ALTER TABLE prices
+    ADD COLUMN IF NOT EXISTS spread_pct NUMERIC(12,6);
+
+COMMENT ON COLUMN prices.spread_pct IS '(max-min)/median*100 по котировкам провайдеров; NULL — цена от одного провайдера';
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE prices
+    DROP COLUMN IF EXISTS circulating_supply,
+    DROP COLUMN IF EXISTS change_24h_pct,
+    DROP COLUMN IF EXISTS low_24h,
+    DROP COLUMN IF EXISTS high_24h,
+    DROP COLUMN IF EXISTS market_cap,
+    DROP COLUMN IF EXISTS volume_24h;
\ No newline at end of file
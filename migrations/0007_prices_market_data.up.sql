@@ -0,0 +1,15 @@
+-- Рыночные показатели на момент цены (CoinGecko /coins/markets); NULL — нет данных у провайдера
+ALTER TABLE prices
+    ADD COLUMN IF NOT EXISTS volume_24h         NUMERIC(30,2),
+    ADD COLUMN IF NOT EXISTS market_cap         NUMERIC(30,2),
+    ADD COLUMN IF NOT EXISTS high_24h           NUMERIC(20,10),
+    ADD COLUMN IF NOT EXISTS low_24h            NUMERIC(20,10),
+    ADD COLUMN IF NOT EXISTS change_24h_pct     NUMERIC(12,6),
+    ADD COLUMN IF NOT EXISTS circulating_supply NUMERIC(30,4);
+
+COMMENT ON COLUMN prices.volume_24h         IS 'Объём торгов за 24ч';
+COMMENT ON COLUMN prices.market_cap         IS 'Рыночная капитализация';
+COMMENT ON COLUMN prices.high_24h           IS 'Максимальная цена за 24ч по данным провайдера';
+COMMENT ON COLUMN prices.low_24h            IS 'Минимальная цена за 24ч по данным провайдера';
+COMMENT ON COLUMN prices.change_24h_pct     IS 'Изменение цены за 24ч, %';
+COMMENT ON COLUMN prices.circulating_supply IS 'Монет в обращении';
\ No newline at end of file
@@ -0,0 +1,13 @@
+DELETE FROM price_quotes WHERE currency <> 'usd';
+ALTER TABLE price_quotes DROP CONSTRAINT IF EXISTS price_quotes_pkey;
+ALTER TABLE price_quotes ADD PRIMARY KEY (coin_symbol, timestamp, source);
+ALTER TABLE price_quotes DROP COLUMN IF EXISTS currency;
+
+DELETE FROM prices WHERE currency <> 'usd';
+DROP INDEX IF EXISTS idx_prices_symbol_currency_ts_desc;
+ALTER TABLE prices DROP CONSTRAINT IF EXISTS prices_pkey;
+ALTER TABLE prices ADD PRIMARY KEY (coin_symbol, timestamp);
+ALTER TABLE prices DROP COLUMN IF EXISTS currency;
+
+CREATE INDEX IF NOT EXISTS idx_prices_symbol_ts_desc
+    ON prices (coin_symbol, timestamp DESC);
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- Валюта котировки: цены хранятся отдельно для каждой vs_currency (usd, eur, rub, btc)
+ALTER TABLE prices
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'usd';
+
+ALTER TABLE prices DROP CONSTRAINT IF EXISTS prices_pkey;
+ALTER TABLE prices ADD PRIMARY KEY (coin_symbol, currency, timestamp);
+
+DROP INDEX IF EXISTS idx_prices_symbol_ts_desc;
+CREATE INDEX IF NOT EXISTS idx_prices_symbol_currency_ts_desc
+    ON prices (coin_symbol, currency, timestamp DESC);
+
+COMMENT ON COLUMN prices.currency IS 'Валюта котировки в нижнем регистре (usd, eur, rub, btc)';
+
+ALTER TABLE price_quotes
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'usd';
+
+ALTER TABLE price_quotes DROP CONSTRAINT IF EXISTS price_quotes_pkey;
+ALTER TABLE price_quotes ADD PRIMARY KEY (coin_symbol, currency, timestamp, source);
\ No newline at end of file
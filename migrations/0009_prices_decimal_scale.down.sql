@@ -0,0 +1,9 @@
+ALTER TABLE price_quotes
+    ALTER COLUMN value TYPE NUMERIC(20,10);
+
+ALTER TABLE prices
+    ALTER COLUMN value    TYPE NUMERIC(20,10),
+    ALTER COLUMN high_24h TYPE NUMERIC(20,10),
+    ALTER COLUMN low_24h  TYPE NUMERIC(20,10);
+
+COMMENT ON COLUMN prices.value IS 'Цена в NUMERIC(20,10)';
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- Точность цен: 10 знаков после запятой мало для токенов вроде SHIB/PEPE в котировке к BTC
+ALTER TABLE prices
+    ALTER COLUMN value    TYPE NUMERIC(38,18),
+    ALTER COLUMN high_24h TYPE NUMERIC(38,18),
+    ALTER COLUMN low_24h  TYPE NUMERIC(38,18);
+
+ALTER TABLE price_quotes
+    ALTER COLUMN value TYPE NUMERIC(38,18);
+
+COMMENT ON COLUMN prices.value IS 'Цена в NUMERIC(38,18)';
\ No newline at end of file
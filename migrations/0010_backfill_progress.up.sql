@@ -0,0 +1,13 @@
+-- Прогресс загрузки истории (команда backfill): повторный запуск того же диапазона продолжает с done_until
+CREATE TABLE IF NOT EXISTS backfill_progress (
+    coin_symbol TEXT NOT NULL REFERENCES coins(symbol) ON DELETE CASCADE,
+    currency    TEXT NOT NULL,
+    range_from  TIMESTAMPTZ NOT NULL,
+    range_to    TIMESTAMPTZ NOT NULL,
+    done_until  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (coin_symbol, currency, range_from, range_to)
+);
+
+COMMENT ON TABLE backfill_progress IS 'Докуда загружена история цен для запрошенного диапазона';
+COMMENT ON COLUMN backfill_progress.done_until IS 'Диапазон [range_from, done_until) уже сохранён в prices';
\ No newline at end of file
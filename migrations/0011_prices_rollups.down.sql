@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS prices_daily;
+DROP TABLE IF EXISTS prices_hourly;
\ No newline at end of file
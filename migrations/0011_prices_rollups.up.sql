@@ -0,0 +1,42 @@
+-- Свёрнутые старые цены (задача scheduler_retention): сырые точки старше keep_raw сворачиваются
+-- в часовые агрегаты, часовые старше keep_hourly — в дневные
+CREATE TABLE IF NOT EXISTS prices_hourly (
+    coin_symbol TEXT NOT NULL REFERENCES coins(symbol) ON DELETE CASCADE,
+    currency    TEXT NOT NULL,
+    bucket      TIMESTAMPTZ NOT NULL,     -- начало часа (UTC)
+    open        NUMERIC(38,18) NOT NULL,
+    high        NUMERIC(38,18) NOT NULL,
+    low         NUMERIC(38,18) NOT NULL,
+    close       NUMERIC(38,18) NOT NULL,
+    points      INTEGER NOT NULL,
+    opened_at   TIMESTAMPTZ NOT NULL,
+    closed_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (coin_symbol, currency, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_prices_hourly_closed_at
+    ON prices_hourly (coin_symbol, currency, closed_at DESC);
+
+COMMENT ON TABLE prices_hourly IS 'Часовые OHLC-агрегаты цен старше keep_raw';
+COMMENT ON COLUMN prices_hourly.points    IS 'Сколько сырых точек свёрнуто в агрегат';
+COMMENT ON COLUMN prices_hourly.opened_at IS 'Время первой свёрнутой точки (цена open)';
+COMMENT ON COLUMN prices_hourly.closed_at IS 'Время последней свёрнутой точки (цена close)';
+
+CREATE TABLE IF NOT EXISTS prices_daily (
+    coin_symbol TEXT NOT NULL REFERENCES coins(symbol) ON DELETE CASCADE,
+    currency    TEXT NOT NULL,
+    bucket      TIMESTAMPTZ NOT NULL,     -- начало суток (UTC)
+    open        NUMERIC(38,18) NOT NULL,
+    high        NUMERIC(38,18) NOT NULL,
+    low         NUMERIC(38,18) NOT NULL,
+    close       NUMERIC(38,18) NOT NULL,
+    points      INTEGER NOT NULL,
+    opened_at   TIMESTAMPTZ NOT NULL,
+    closed_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (coin_symbol, currency, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_prices_daily_closed_at
+    ON prices_daily (coin_symbol, currency, closed_at DESC);
+
+COMMENT ON TABLE prices_daily IS 'Дневные OHLC-агрегаты цен старше keep_hourly';
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- Обратно к обычной таблице: все секции сливаются в одну
+CREATE TABLE prices_unpartitioned (
+    coin_symbol        TEXT NOT NULL REFERENCES coins(symbol) ON DELETE CASCADE,
+    currency           TEXT NOT NULL DEFAULT 'usd',
+    timestamp          TIMESTAMPTZ NOT NULL,
+    value              NUMERIC(38,18) NOT NULL,
+    source             TEXT NOT NULL DEFAULT '',
+    spread_pct         NUMERIC(12,6),
+    volume_24h         NUMERIC(30,2),
+    market_cap         NUMERIC(30,2),
+    high_24h           NUMERIC(38,18),
+    low_24h            NUMERIC(38,18),
+    change_24h_pct     NUMERIC(12,6),
+    circulating_supply NUMERIC(30,4),
+    CONSTRAINT value_positive CHECK (value > 0)
+);
+
+INSERT INTO prices_unpartitioned
+SELECT coin_symbol, currency, timestamp, value, source, spread_pct,
+       volume_24h, market_cap, high_24h, low_24h, change_24h_pct, circulating_supply
+FROM prices;
+
+DROP TABLE prices;
+ALTER TABLE prices_unpartitioned RENAME TO prices;
+ALTER TABLE prices ADD PRIMARY KEY (coin_symbol, currency, timestamp);
+CREATE INDEX IF NOT EXISTS idx_prices_symbol_currency_ts_desc
+    ON prices (coin_symbol, currency, timestamp DESC);
\ No newline at end of file
@@ -0,0 +1,62 @@
+-- Помесячное секционирование prices (RANGE по timestamp, границы — начало месяца UTC).
+-- Будущие секции заранее создаёт менеджер секций (команда partitions / задача scheduler_partitions),
+-- истёкшие — отсоединяет или удаляет. Строки вне созданных секций (например, старая история из backfill)
+-- попадают в prices_default.
+ALTER TABLE prices RENAME TO prices_unpartitioned;
+ALTER TABLE prices_unpartitioned RENAME CONSTRAINT prices_pkey TO prices_unpartitioned_pkey;
+DROP INDEX IF EXISTS idx_prices_symbol_currency_ts_desc;
+
+CREATE TABLE prices (
+    coin_symbol        TEXT NOT NULL REFERENCES coins(symbol) ON DELETE CASCADE,
+    currency           TEXT NOT NULL DEFAULT 'usd',
+    timestamp          TIMESTAMPTZ NOT NULL,
+    value              NUMERIC(38,18) NOT NULL,
+    source             TEXT NOT NULL DEFAULT '',
+    spread_pct         NUMERIC(12,6),
+    volume_24h         NUMERIC(30,2),
+    market_cap         NUMERIC(30,2),
+    high_24h           NUMERIC(38,18),
+    low_24h            NUMERIC(38,18),
+    change_24h_pct     NUMERIC(12,6),
+    circulating_supply NUMERIC(30,4),
+    CONSTRAINT value_positive CHECK (value > 0),
+    -- индекс первичного ключа обслуживает и выборки по убыванию времени (обратный проход),
+    -- отдельный idx_prices_symbol_currency_ts_desc больше не нужен
+    PRIMARY KEY (coin_symbol, currency, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+COMMENT ON TABLE prices IS 'Исторические цены криптовалют, секции по месяцам (prices_YYYY_MM)';
+COMMENT ON COLUMN prices.coin_symbol IS 'Ссылка на coins(symbol)';
+COMMENT ON COLUMN prices.currency    IS 'Валюта котировки в нижнем регистре (usd, eur, rub, btc)';
+COMMENT ON COLUMN prices.timestamp   IS 'Момент фиксации цены (UTC)';
+COMMENT ON COLUMN prices.value       IS 'Цена в NUMERIC(38,18)';
+
+-- Секции от месяца самой старой цены до двух месяцев вперёд
+DO $$
+DECLARE
+    m    TIMESTAMP;
+    last TIMESTAMP := date_trunc('month', now() AT TIME ZONE 'UTC') + interval '2 months';
+BEGIN
+    SELECT COALESCE(date_trunc('month', min(timestamp) AT TIME ZONE 'UTC'), date_trunc('month', now() AT TIME ZONE 'UTC'))
+    INTO m
+    FROM prices_unpartitioned;
+
+    WHILE m <= last LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF prices FOR VALUES FROM (%L) TO (%L)',
+                       'prices_' || to_char(m, 'YYYY_MM'),
+                       m AT TIME ZONE 'UTC',
+                       (m + interval '1 month') AT TIME ZONE 'UTC');
+        m := m + interval '1 month';
+    END LOOP;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS prices_default PARTITION OF prices DEFAULT;
+
+INSERT INTO prices (coin_symbol, currency, timestamp, value, source, spread_pct,
+                    volume_24h, market_cap, high_24h, low_24h, change_24h_pct, circulating_supply)
+SELECT coin_symbol, currency, timestamp, value, source, spread_pct,
+       volume_24h, market_cap, high_24h, low_24h, change_24h_pct, circulating_supply
+FROM prices_unpartitioned;
+
+DROP TABLE prices_unpartitioned;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- Ценовые алерты Telegram: уведомление при пересечении ценой уровня (команды /alert, /alerts, /delalert)
+CREATE TABLE IF NOT EXISTS alerts (
+    id           BIGSERIAL PRIMARY KEY,
+    chat_id      BIGINT NOT NULL,
+    coin_symbol  TEXT NOT NULL REFERENCES coins(symbol) ON DELETE CASCADE,
+    currency     TEXT NOT NULL,
+    direction    TEXT NOT NULL CHECK (direction IN ('above', 'below')),
+    level        NUMERIC(38,18) NOT NULL CHECK (level > 0),
+    armed        BOOLEAN NOT NULL DEFAULT TRUE,
+    triggered_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_chat ON alerts (chat_id);
+CREATE INDEX IF NOT EXISTS idx_alerts_symbol ON alerts (coin_symbol);
+
+COMMENT ON TABLE alerts IS 'Алерты на пересечение ценой уровня; срабатывают один раз на пересечение';
+COMMENT ON COLUMN alerts.armed IS 'Ждёт пересечения; после срабатывания FALSE, пока цена не вернётся за уровень с гистерезисом';
+COMMENT ON COLUMN alerts.triggered_at IS 'Последнее срабатывание';
\ No newline at end of file
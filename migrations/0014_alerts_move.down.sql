@@ -0,0 +1,15 @@
+DELETE FROM alerts WHERE kind = 'move';
+
+ALTER TABLE alerts
+    DROP CONSTRAINT IF EXISTS alerts_cooldown_check,
+    DROP CONSTRAINT IF EXISTS alerts_move_window_check,
+    DROP CONSTRAINT IF EXISTS alerts_direction_check,
+    DROP CONSTRAINT IF EXISTS alerts_kind_check;
+ALTER TABLE alerts ADD CONSTRAINT alerts_direction_check CHECK (direction IN ('above', 'below'));
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS cooldown_seconds,
+    DROP COLUMN IF EXISTS window_seconds,
+    DROP COLUMN IF EXISTS kind;
+
+COMMENT ON COLUMN alerts.level IS NULL;
\ No newline at end of file
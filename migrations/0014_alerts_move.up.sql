@@ -0,0 +1,19 @@
+-- Алерты на изменение цены за окно (/movealert BTC 5% 1h): порог в процентах хранится в level
+ALTER TABLE alerts
+    ADD COLUMN IF NOT EXISTS kind             TEXT NOT NULL DEFAULT 'level',
+    ADD COLUMN IF NOT EXISTS window_seconds   INT,
+    ADD COLUMN IF NOT EXISTS cooldown_seconds INT NOT NULL DEFAULT 0;
+
+ALTER TABLE alerts DROP CONSTRAINT IF EXISTS alerts_direction_check;
+ALTER TABLE alerts
+    ADD CONSTRAINT alerts_kind_check CHECK (kind IN ('level', 'move')),
+    ADD CONSTRAINT alerts_direction_check CHECK (
+        direction IN ('above', 'below') OR (kind = 'move' AND direction = 'any')
+    ),
+    ADD CONSTRAINT alerts_move_window_check CHECK (kind <> 'move' OR window_seconds > 0),
+    ADD CONSTRAINT alerts_cooldown_check CHECK (cooldown_seconds >= 0);
+
+COMMENT ON COLUMN alerts.kind IS 'level — пересечение уровня, move — изменение цены за окно';
+COMMENT ON COLUMN alerts.level IS 'level: цена; move: порог изменения в процентах';
+COMMENT ON COLUMN alerts.window_seconds IS 'move: окно изменения';
+COMMENT ON COLUMN alerts.cooldown_seconds IS 'move: пауза после срабатывания';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS coins;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Выбор монет для авторассылки (/startauto 10 ETH SOL); пустой массив — все отслеживаемые монеты
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS coins TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN subscriptions.coins IS 'Символы монет рассылки; пустой массив — все отслеживаемые';
\ No newline at end of file
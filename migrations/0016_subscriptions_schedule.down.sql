@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS chat_settings;
+
+DROP INDEX IF EXISTS idx_subscriptions_next_run;
+DELETE FROM subscriptions WHERE schedule_kind <> 'interval';
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_interval_required;
+ALTER TABLE subscriptions ALTER COLUMN interval_minutes SET NOT NULL;
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS next_run_at,
+    DROP COLUMN IF EXISTS schedule_spec,
+    DROP COLUMN IF EXISTS schedule_kind;
\ No newline at end of file
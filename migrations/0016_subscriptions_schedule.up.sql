@@ -0,0 +1,25 @@
+-- Расписания авторассылки: интервал, ежедневно в заданное время, cron — и часовой пояс чата.
+-- Время следующей рассылки считается в приложении (next_run_at): так оно корректно переживает переходы на летнее время.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS schedule_kind TEXT NOT NULL DEFAULT 'interval'
+        CHECK (schedule_kind IN ('interval', 'daily', 'cron')),
+    ADD COLUMN IF NOT EXISTS schedule_spec TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS next_run_at   TIMESTAMPTZ;
+
+ALTER TABLE subscriptions ALTER COLUMN interval_minutes DROP NOT NULL;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_interval_required
+    CHECK (schedule_kind <> 'interval' OR interval_minutes IS NOT NULL);
+
+UPDATE subscriptions
+SET next_run_at = COALESCE(last_sent_at + make_interval(mins => interval_minutes), now())
+WHERE next_run_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_next_run
+    ON subscriptions (next_run_at) WHERE enabled;
+
+COMMENT ON COLUMN subscriptions.schedule_spec IS 'daily: HH:MM; cron: выражение из 5 полей; по часовому поясу чата';
+
+CREATE TABLE IF NOT EXISTS chat_settings (
+    chat_id  BIGINT PRIMARY KEY,
+    timezone TEXT NOT NULL DEFAULT 'UTC'
+);
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS missed_since;
+
+ALTER TABLE chat_settings
+    DROP CONSTRAINT IF EXISTS chat_settings_quiet_bounds,
+    DROP COLUMN IF EXISTS quiet_summary,
+    DROP COLUMN IF EXISTS quiet_end,
+    DROP COLUMN IF EXISTS quiet_start;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- Тихие часы чата (/quiet 23:00-08:00): границы в минутах от полуночи по часовому поясу чата
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS quiet_start   SMALLINT CHECK (quiet_start BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS quiet_end     SMALLINT CHECK (quiet_end BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS quiet_summary BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT chat_settings_quiet_bounds
+        CHECK ((quiet_start IS NULL) = (quiet_end IS NULL) AND quiet_start <> quiet_end);
+
+-- Первая рассылка, пропущенная в тихие часы: по ней после окончания тишины отправляется сводка
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS missed_since TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE backfill_progress DROP CONSTRAINT IF EXISTS backfill_progress_pkey;
+ALTER TABLE backfill_progress ADD COLUMN IF NOT EXISTS range_to TIMESTAMPTZ;
+UPDATE backfill_progress SET range_to = done_until WHERE range_to IS NULL;
+ALTER TABLE backfill_progress ALTER COLUMN range_to SET NOT NULL;
+ALTER TABLE backfill_progress ADD PRIMARY KEY (coin_symbol, currency, range_from, range_to);
+
+COMMENT ON TABLE backfill_progress IS 'Докуда загружена история цен для запрошенного диапазона';
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- Прогресс backfill привязан к началу диапазона: повторный запуск без -to (to = сейчас) продолжает
+-- с done_until, а более поздний to лишь продлевает уже загруженный диапазон
+DELETE FROM backfill_progress p
+USING backfill_progress q
+WHERE q.coin_symbol = p.coin_symbol AND q.currency = p.currency AND q.range_from = p.range_from
+  AND (q.done_until > p.done_until OR (q.done_until = p.done_until AND q.range_to > p.range_to));
+
+ALTER TABLE backfill_progress DROP CONSTRAINT IF EXISTS backfill_progress_pkey;
+ALTER TABLE backfill_progress DROP COLUMN IF EXISTS range_to;
+ALTER TABLE backfill_progress ADD PRIMARY KEY (coin_symbol, currency, range_from);
+
+COMMENT ON TABLE backfill_progress IS 'Докуда загружена история цен от начала запрошенного диапазона';
\ No newline at end of file
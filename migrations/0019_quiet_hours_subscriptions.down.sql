@@ -0,0 +1,20 @@
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS quiet_start   SMALLINT CHECK (quiet_start BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS quiet_end     SMALLINT CHECK (quiet_end BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS quiet_summary BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT chat_settings_quiet_bounds
+        CHECK ((quiet_start IS NULL) = (quiet_end IS NULL) AND quiet_start <> quiet_end);
+
+INSERT INTO chat_settings (chat_id, quiet_start, quiet_end, quiet_summary)
+SELECT chat_id, quiet_start, quiet_end, quiet_summary
+FROM subscriptions
+WHERE quiet_start IS NOT NULL
+ON CONFLICT (chat_id) DO UPDATE SET quiet_start = EXCLUDED.quiet_start,
+                                    quiet_end = EXCLUDED.quiet_end,
+                                    quiet_summary = EXCLUDED.quiet_summary;
+
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_quiet_bounds,
+    DROP COLUMN IF EXISTS quiet_start,
+    DROP COLUMN IF EXISTS quiet_end,
+    DROP COLUMN IF EXISTS quiet_summary;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- Тихие часы хранятся с подпиской, как и её расписание: FindDue читает их из той же строки.
+-- Тихие часы чатов без подписки не переносятся — без подписки им нечего откладывать.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS quiet_start   SMALLINT CHECK (quiet_start BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS quiet_end     SMALLINT CHECK (quiet_end BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS quiet_summary BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT subscriptions_quiet_bounds
+        CHECK ((quiet_start IS NULL) = (quiet_end IS NULL) AND quiet_start <> quiet_end);
+
+UPDATE subscriptions s
+SET quiet_start = cs.quiet_start, quiet_end = cs.quiet_end, quiet_summary = cs.quiet_summary
+FROM chat_settings cs
+WHERE cs.chat_id = s.chat_id AND cs.quiet_start IS NOT NULL;
+
+ALTER TABLE chat_settings
+    DROP CONSTRAINT IF EXISTS chat_settings_quiet_bounds,
+    DROP COLUMN IF EXISTS quiet_start,
+    DROP COLUMN IF EXISTS quiet_end,
+    DROP COLUMN IF EXISTS quiet_summary;
+
+COMMENT ON COLUMN subscriptions.quiet_start IS 'Начало тихих часов, минуты от полуночи по часовому поясу чата';
\ No newline at end of file
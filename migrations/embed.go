@@ -0,0 +1,9 @@
+// Package migrations — SQL-миграции схемы, встроенные в бинарник (команда `migrate`).
+package migrations
+
+import "embed"
+
+// FS — файлы миграций NNNN_name.up.sql / NNNN_name.down.sql
+//
+//go:embed *.sql
+var FS embed.FS